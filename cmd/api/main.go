@@ -13,6 +13,7 @@ import (
 	"github.com/gorilla/websocket"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/joho/godotenv"
+	"log/slog"
 	"net/http"
 	"os"
 	"time"
@@ -42,6 +43,12 @@ func main() {
 		panic(err)
 	}
 
+	if key := os.Getenv("GOBID_PSEUDONYM_KEY"); key != "" {
+		services.SetPseudonymKey([]byte(key))
+	} else {
+		slog.Warn("GOBID_PSEUDONYM_KEY is not set, bidder pseudonyms will differ between instances and restarts")
+	}
+
 	s := scs.New()
 	s.Store = pgxstore.New(pool)
 	s.Lifetime = 24 * time.Hour
@@ -49,11 +56,14 @@ func main() {
 	s.Cookie.SameSite = http.SameSiteLaxMode
 
 	api := api.Api{
-		Router:         chi.NewMux(),
-		UserService:    services.NewUserService(pool),
-		ProductService: services.NewProductsService(pool),
-		BidsService:    services.NewBidsService(pool),
-		Sessions:       s,
+		Router:              chi.NewMux(),
+		UserService:         services.NewUserService(pool),
+		ProductService:      services.NewProductsService(pool),
+		BidsService:         services.NewBidsService(pool),
+		IdempotencyService:  services.NewIdempotencyService(pool),
+		TaxonomyService:     services.NewTaxonomyService(pool),
+		NotificationService: services.NewNotificationService(pool),
+		Sessions:            s,
 		WsUpgrader: websocket.Upgrader{
 			CheckOrigin: func(r *http.Request) bool {
 				allowedOrigins := []string{
@@ -72,8 +82,46 @@ func main() {
 			Rooms: make(map[uuid.UUID]*services.AuctionRoom),
 		},
 	}
+	if path := os.Getenv("GOBID_EXCHANGE_RATES_FILE"); path != "" {
+		rates, err := services.LoadRatesFile(path)
+		if err != nil {
+			panic(err)
+		}
+		api.ExchangeRates = rates
+	}
+	mediaDir, mediaURL := os.Getenv("GOBID_MEDIA_DIR"), os.Getenv("GOBID_MEDIA_URL")
+	if mediaDir == "" {
+		mediaDir = "media"
+	}
+	if mediaURL == "" {
+		mediaURL = "/media"
+	}
+	blobs, err := services.NewLocalBlobStore(mediaDir, mediaURL)
+	if err != nil {
+		panic(err)
+	}
+	api.ProductService.Blobs = blobs
+	switch broadcaster := os.Getenv("GOBID_BROADCASTER"); broadcaster {
+	case "", "postgres":
+		pg := services.NewPostgresBroadcaster(pool)
+		api.Broadcaster, api.Elector = pg, pg
+		go func() {
+			if err := pg.Listen(ctx); err != nil {
+				slog.Error("Auction events listener stopped", "error", err)
+			}
+		}()
+	case "memory":
+		api.Broadcaster = services.NewMemoryBroadcaster()
+	default:
+		panic(fmt.Sprintf("unknown broadcaster %q", broadcaster))
+	}
 	api.BindRoutes()
 
+	if err := api.RestoreAuctionRooms(ctx); err != nil {
+		panic(err)
+	}
+	go api.RunSettlementSweeper(ctx)
+
 	fmt.Println("Starting server on port :3080")
 	if err := http.ListenAndServe("localhost:3080", api.Router); err != nil {
 		panic(err)
@@ -22,9 +22,9 @@ func main() {
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		fmt.Printf("Command execution failed with %s\n", err)
-		fmt.Printf("Output: ", string(output))
+		fmt.Printf("Output: %s\n", string(output))
 		panic(err)
 	}
 
-	fmt.Printf("Command executed successfuly ", string(output))
+	fmt.Printf("Command executed successfuly %s\n", string(output))
 }
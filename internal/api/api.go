@@ -8,11 +8,17 @@ import (
 )
 
 type Api struct {
-	Router         *chi.Mux
-	UserService    services.UserService
-	ProductService services.ProductsService
-	Sessions       *scs.SessionManager
-	WsUpgrader     websocket.Upgrader
-	AuctionLobby   services.AuctionLobby
-	BidsService    services.BidsService
+	Router              *chi.Mux
+	UserService         services.UserService
+	ProductService      services.ProductsService
+	Sessions            *scs.SessionManager
+	WsUpgrader          websocket.Upgrader
+	AuctionLobby        services.AuctionLobby
+	BidsService         services.BidsService
+	IdempotencyService  services.IdempotencyService
+	TaxonomyService     services.TaxonomyService
+	NotificationService services.NotificationService
+	ExchangeRates       services.ExchangeRateProvider
+	Broadcaster         services.Broadcaster
+	Elector             services.SettlementElector
 }
@@ -6,7 +6,11 @@ import (
 	"github.com/FelipePn10/Gobid/internal/services"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"log/slog"
 	"net/http"
+	"strconv"
+	"time"
 )
 
 func (api *Api) handlerSubscribeUsertoAuction(w http.ResponseWriter, r *http.Request) {
@@ -24,12 +28,27 @@ func (api *Api) handlerSubscribeUsertoAuction(w http.ResponseWriter, r *http.Req
 			jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
 				"message": "product not found",
 			})
+			return
 		}
 		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
 			"message": "internal server error",
 		})
 		return
 	}
+	lastEvent, ok := requestedLastEvent(r)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"message": "invalid last_event_id or last_seq - must be the cursor or sequence number of an event",
+		})
+		return
+	}
+	displayCurrency, ok := requestedDisplayCurrency(r)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"message": "unsupported display currency",
+		})
+		return
+	}
 	userId, ok := api.Sessions.Get(r.Context(), "AuthenticatedUserId").(uuid.UUID)
 	if !ok {
 		jsonutils.EncodeJson(w, r, http.StatusUnauthorized, map[string]any{
@@ -37,9 +56,7 @@ func (api *Api) handlerSubscribeUsertoAuction(w http.ResponseWriter, r *http.Req
 		})
 		return
 	}
-	api.AuctionLobby.Lock()
-	room, ok := api.AuctionLobby.Rooms[productId]
-	api.AuctionLobby.Unlock()
+	room, ok := api.getAuctionRoom(r.Context(), productId)
 	if !ok {
 		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
 			"message": "the auction has ended",
@@ -53,9 +70,110 @@ func (api *Api) handlerSubscribeUsertoAuction(w http.ResponseWriter, r *http.Req
 		})
 		return
 	}
-	client := services.NewClient(room, conn, userId)
+	client := services.NewClient(room, userId, conn)
+	client.DisplayCurrency = displayCurrency
+	client.LastEvent = lastEvent
 
-	room.Register <- client
+	select {
+	case room.Register <- client:
+	case <-room.Done():
+		// The room closed after it was looked up, so nothing will ever read
+		// from this connection.
+		closeMessage := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "the auction has ended")
+		conn.WriteControl(websocket.CloseMessage, closeMessage, time.Now().Add(time.Second))
+		conn.Close()
+		return
+	}
 	go client.ReadEventLoop()
 	go client.WriteEventLoop()
 }
+
+// requestedDisplayCurrency reads the optional display_currency query
+// parameter clients use to get amounts converted alongside the listing price.
+func requestedDisplayCurrency(r *http.Request) (string, bool) {
+	currency := r.URL.Query().Get("display_currency")
+	if currency == "" {
+		return "", true
+	}
+	return currency, services.IsSupportedCurrency(currency)
+}
+
+// requestedLastEvent reads the cursor of the last event a reconnecting client
+// saw, from the last_event_id query parameter or the Last-Event-ID header
+// browsers send when an event stream reconnects. A bare last_seq query
+// parameter names an event of the room's current run.
+func requestedLastEvent(r *http.Request) (*services.EventCursor, bool) {
+	raw := r.URL.Query().Get("last_event_id")
+	if raw == "" {
+		raw = r.Header.Get("Last-Event-ID")
+	}
+	if raw == "" {
+		rawSeq := r.URL.Query().Get("last_seq")
+		if rawSeq == "" {
+			return nil, true
+		}
+		lastSeq, err := strconv.ParseUint(rawSeq, 10, 64)
+		if err != nil {
+			return nil, false
+		}
+		return &services.EventCursor{Seq: lastSeq}, true
+	}
+	cursor, err := services.ParseEventCursor(raw)
+	if err != nil {
+		return nil, false
+	}
+	return &cursor, true
+}
+
+func (api *Api) handleAuctionEvents(w http.ResponseWriter, r *http.Request) {
+	productId, err := uuid.Parse(chi.URLParam(r, "product_id"))
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"message": "invalid product id - must be a valid uuid",
+		})
+		return
+	}
+	lastEvent, ok := requestedLastEvent(r)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"message": "invalid last_event_id or last_seq - must be the cursor or sequence number of an event",
+		})
+		return
+	}
+	displayCurrency, ok := requestedDisplayCurrency(r)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"message": "unsupported display currency",
+		})
+		return
+	}
+	userId, ok := api.Sessions.Get(r.Context(), "AuthenticatedUserId").(uuid.UUID)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusUnauthorized, map[string]any{
+			"message": "unexpected error, try again later",
+		})
+		return
+	}
+	room, ok := api.getAuctionRoom(r.Context(), productId)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+			"message": "the auction is not running",
+		})
+		return
+	}
+	client := services.NewClient(room, userId, nil)
+	client.DisplayCurrency = displayCurrency
+	client.LastEvent = lastEvent
+
+	select {
+	case room.Register <- client:
+	case <-room.Done():
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"message": "the auction has ended",
+		})
+		return
+	}
+	if err := client.StreamEventLoop(r.Context(), w); err != nil {
+		slog.Error("Event stream stopped", "auctionID", productId, "user_id", userId, "error", err)
+	}
+}
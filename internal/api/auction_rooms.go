@@ -0,0 +1,131 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"github.com/FelipePn10/Gobid/internal/services"
+	"github.com/FelipePn10/Gobid/internal/store/pgstore"
+	"github.com/google/uuid"
+	"log/slog"
+	"time"
+)
+
+const (
+	settlementSweepInterval = time.Minute
+	settlementGracePeriod   = 2 * time.Minute
+)
+
+// startAuctionRoom opens the room for product, returning the existing one if
+// the auction already has a room on this instance.
+func (api *Api) startAuctionRoom(product pgstore.Product) *services.AuctionRoom {
+	productId := product.ID
+	api.AuctionLobby.Lock()
+	defer api.AuctionLobby.Unlock()
+	if room, ok := api.AuctionLobby.Rooms[productId]; ok {
+		return room
+	}
+
+	ctx, cancel := context.WithDeadline(context.Background(), product.AuctionEnd)
+	auctionRoom := services.NewAuctionRoom(ctx, productId, api.BidsService)
+	auctionRoom.StartsAt = product.AuctionStart
+	auctionRoom.Currency = product.Currency
+	auctionRoom.Rates = api.ExchangeRates
+	if api.Broadcaster != nil {
+		auctionRoom.Broadcaster = api.Broadcaster
+	}
+	auctionRoom.Elector = api.Elector
+	if schedule, ok := services.DutchScheduleFor(product); ok {
+		auctionRoom.Dutch = &schedule
+	}
+
+	go func() {
+		defer cancel()
+		auctionRoom.Run()
+
+		api.AuctionLobby.Lock()
+		if api.AuctionLobby.Rooms[productId] == auctionRoom {
+			delete(api.AuctionLobby.Rooms, productId)
+		}
+		api.AuctionLobby.Unlock()
+	}()
+
+	api.AuctionLobby.Rooms[productId] = auctionRoom
+	return auctionRoom
+}
+
+// getAuctionRoom returns the room of a running auction, opening it when the
+// auction was created or restored by another instance.
+func (api *Api) getAuctionRoom(ctx context.Context, productId uuid.UUID) (*services.AuctionRoom, bool) {
+	api.AuctionLobby.Lock()
+	room, ok := api.AuctionLobby.Rooms[productId]
+	api.AuctionLobby.Unlock()
+	if ok {
+		return room, true
+	}
+
+	product, err := api.ProductService.GetProductByID(ctx, productId)
+	if err != nil {
+		if !errors.Is(err, services.ErrProductNotFound) {
+			slog.Error("Failed to load auction", "auctionID", productId, "error", err)
+		}
+		return nil, false
+	}
+	if status := services.EffectiveStatus(product, time.Now()); status != services.ProductScheduled && status != services.ProductLive {
+		return nil, false
+	}
+	return api.startAuctionRoom(product), true
+}
+
+// RestoreAuctionRooms reopens a room for every unsold product whose auction is
+// still running, so restarting the server does not end live auctions, and
+// settles the auctions that ended while it was down.
+func (api *Api) RestoreAuctionRooms(ctx context.Context) error {
+	if err := api.BidsService.SettleOverdueAuctions(ctx, time.Now()); err != nil {
+		slog.Error("Failed to settle overdue auctions", "error", err)
+	}
+	products, err := api.ProductService.GetActiveAuctions(ctx)
+	if err != nil {
+		return err
+	}
+	for _, product := range products {
+		api.startAuctionRoom(product)
+	}
+	slog.Info("Auction rooms restored", "count", len(products))
+	return nil
+}
+
+// RunSettlementSweeper settles, until ctx is done, the auctions no room
+// settled: those that ended while no instance was running and those whose
+// room gave up after failing. Rooms get a grace period to settle their own.
+func (api *Api) RunSettlementSweeper(ctx context.Context) {
+	ticker := time.NewTicker(settlementSweepInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+		if err := api.BidsService.SettleOverdueAuctions(ctx, time.Now().Add(-settlementGracePeriod)); err != nil {
+			slog.Error("Failed to settle overdue auctions", "error", err)
+		}
+	}
+}
+
+// publishAuctionEvent tells every instance's room for productId about m. An
+// auction without a running room has nobody to tell.
+func (api *Api) publishAuctionEvent(ctx context.Context, productId uuid.UUID, m services.Message) {
+	broadcaster := api.Broadcaster
+	if broadcaster == nil {
+		api.AuctionLobby.Lock()
+		room, ok := api.AuctionLobby.Rooms[productId]
+		api.AuctionLobby.Unlock()
+		if !ok {
+			return
+		}
+		broadcaster = room.Broadcaster
+	}
+	if err := broadcaster.Publish(context.WithoutCancel(ctx), productId, m); err != nil {
+		slog.Error("Failed to publish auction event", "auctionID", productId, "kind", m.Kind, "error", err)
+	}
+}
@@ -2,6 +2,7 @@ package api
 
 import (
 	"github.com/FelipePn10/Gobid/internal/jsonutils"
+	"github.com/google/uuid"
 	"github.com/gorilla/csrf"
 	"net/http"
 )
@@ -24,3 +25,30 @@ func (api *Api) AuthMiddleware(next http.Handler) http.Handler {
 		next.ServeHTTP(w, r)
 	})
 }
+
+// AdminMiddleware must run after AuthMiddleware.
+func (api *Api) AdminMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userId, ok := api.Sessions.Get(r.Context(), "AuthenticatedUserId").(uuid.UUID)
+		if !ok {
+			jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+				"error": "unexpected error, try again later",
+			})
+			return
+		}
+		isAdmin, err := api.UserService.IsAdmin(r.Context(), userId)
+		if err != nil {
+			jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+				"error": "unexpected error, try again later",
+			})
+			return
+		}
+		if !isAdmin {
+			jsonutils.EncodeJson(w, r, http.StatusForbidden, map[string]any{
+				"message": "must be an administrator",
+			})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
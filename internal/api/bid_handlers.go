@@ -0,0 +1,205 @@
+package api
+
+import (
+	"errors"
+	"github.com/FelipePn10/Gobid/internal/jsonutils"
+	"github.com/FelipePn10/Gobid/internal/services"
+	"github.com/FelipePn10/Gobid/internal/usecase/bid"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"net/http"
+)
+
+func (api *Api) handlePlaceMaxBid(w http.ResponseWriter, r *http.Request) {
+	productId, err := uuid.Parse(chi.URLParam(r, "product_id"))
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "invalid product id - must be a valid uuid",
+		})
+		return
+	}
+	displayCurrency, ok := requestedDisplayCurrency(r)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "unsupported display currency",
+		})
+		return
+	}
+	data, problems, err := jsonutils.DecodeValidJson[bid.PlaceMaxBidReq](r)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+	userId, ok := api.Sessions.Get(r.Context(), "AuthenticatedUserId").(uuid.UUID)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected error, try again later",
+		})
+		return
+	}
+	room, ok := api.getAuctionRoom(r.Context(), productId)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "the auction has ended",
+		})
+		return
+	}
+	reply, err := room.Submit(r.Context(), services.Message{
+		Kind:      services.PlaceBid,
+		MaxAmount: &data.MaxAmount,
+		UserId:    userId,
+	})
+	if err != nil {
+		api.encodeBidError(w, r, reply, err, displayCurrency, "failed to place maximum bid, try again later")
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusCreated, map[string]any{
+		"message":              reply.Message,
+		"max_amount":           reply.MaxAmount,
+		"converted_max_amount": services.DisplayAmount(r.Context(), api.ExchangeRates, reply.MaxAmount, displayCurrency),
+	})
+}
+
+func (api *Api) handlePlaceBid(w http.ResponseWriter, r *http.Request) {
+	productId, err := uuid.Parse(chi.URLParam(r, "product_id"))
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "invalid product id - must be a valid uuid",
+		})
+		return
+	}
+	displayCurrency, ok := requestedDisplayCurrency(r)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "unsupported display currency",
+		})
+		return
+	}
+	data, problems, err := jsonutils.DecodeValidJson[bid.PlaceBidReq](r)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+	userId, ok := api.Sessions.Get(r.Context(), "AuthenticatedUserId").(uuid.UUID)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected error, try again later",
+		})
+		return
+	}
+	room, ok := api.getAuctionRoom(r.Context(), productId)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "the auction has ended",
+		})
+		return
+	}
+	reply, err := room.Submit(r.Context(), services.Message{
+		Kind:   services.PlaceBid,
+		Amount: &data.Amount,
+		UserId: userId,
+	})
+	if err != nil {
+		api.encodeBidError(w, r, reply, err, displayCurrency, "failed to place bid, try again later")
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusCreated, map[string]any{
+		"message":          reply.Message,
+		"amount":           reply.Amount,
+		"converted_amount": services.DisplayAmount(r.Context(), api.ExchangeRates, reply.Amount, displayCurrency),
+		"reserve_met":      reply.ReserveMet,
+	})
+}
+
+// encodeBidError maps the reply of a rejected bid to its HTTP response.
+func (api *Api) encodeBidError(w http.ResponseWriter, r *http.Request, reply services.Message, err error, displayCurrency, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrBidIsTooLow), errors.Is(err, services.ErrCurrencyMismatch):
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{
+			"error": reply.Message,
+		})
+	case errors.Is(err, services.ErrBidBelowIncrement):
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{
+			"error":                  reply.Message,
+			"next_minimum":           reply.Amount,
+			"converted_next_minimum": services.DisplayAmount(r.Context(), api.ExchangeRates, reply.Amount, displayCurrency),
+		})
+	case errors.Is(err, services.ErrWrongAuctionType), errors.Is(err, services.ErrAuctionNotStarted):
+		jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{
+			"error": err.Error(),
+		})
+	case errors.Is(err, services.ErrProductNotFound):
+		jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+			"error": "product not found",
+		})
+	case errors.Is(err, services.ErrAuctionHasEnded):
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "the auction has ended",
+		})
+	default:
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": fallback,
+		})
+	}
+}
+
+func (api *Api) handleBuyNow(w http.ResponseWriter, r *http.Request) {
+	productId, err := uuid.Parse(chi.URLParam(r, "product_id"))
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "invalid product id - must be a valid uuid",
+		})
+		return
+	}
+	displayCurrency, ok := requestedDisplayCurrency(r)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "unsupported display currency",
+		})
+		return
+	}
+	userId, ok := api.Sessions.Get(r.Context(), "AuthenticatedUserId").(uuid.UUID)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected error, try again later",
+		})
+		return
+	}
+	room, ok := api.getAuctionRoom(r.Context(), productId)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "the auction has ended",
+		})
+		return
+	}
+	reply, err := room.Submit(r.Context(), services.Message{
+		Kind:   services.BuyNow,
+		UserId: userId,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrBuyNowUnavailable), errors.Is(err, services.ErrAuctionNotStarted):
+			jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{
+				"error": err.Error(),
+			})
+		case errors.Is(err, services.ErrProductNotFound):
+			jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+				"error": "product not found",
+			})
+		case errors.Is(err, services.ErrAuctionHasEnded):
+			jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+				"error": "the auction has ended",
+			})
+		default:
+			jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+				"error": "failed to buy product, try again later",
+			})
+		}
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{
+		"message":         reply.Message,
+		"price":           reply.Amount,
+		"converted_price": services.DisplayAmount(r.Context(), api.ExchangeRates, reply.Amount, displayCurrency),
+	})
+}
@@ -0,0 +1,99 @@
+package api
+
+import (
+	"errors"
+	"github.com/FelipePn10/Gobid/internal/jsonutils"
+	"github.com/FelipePn10/Gobid/internal/services"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"net/http"
+	"strconv"
+)
+
+func (api *Api) handleListBids(w http.ResponseWriter, r *http.Request) {
+	productId, err := uuid.Parse(chi.URLParam(r, "product_id"))
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "invalid product id - must be a valid uuid",
+		})
+		return
+	}
+	pageSize, ok := requestedPageSize(r)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "limit must be a number between 1 and 100",
+		})
+		return
+	}
+	userId, ok := api.Sessions.Get(r.Context(), "AuthenticatedUserId").(uuid.UUID)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected error, try again later",
+		})
+		return
+	}
+	page, err := api.BidsService.ListBids(r.Context(), productId, userId, r.URL.Query().Get("cursor"), pageSize)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidCursor):
+			jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+				"error": err.Error(),
+			})
+		case errors.Is(err, services.ErrProductNotFound):
+			jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+				"error": "product not found",
+			})
+		default:
+			jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+				"error": "failed to list bids, try again later",
+			})
+		}
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusOK, page)
+}
+
+func (api *Api) handleAuctionTimeline(w http.ResponseWriter, r *http.Request) {
+	productId, err := uuid.Parse(chi.URLParam(r, "product_id"))
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "invalid product id - must be a valid uuid",
+		})
+		return
+	}
+	userId, ok := api.Sessions.Get(r.Context(), "AuthenticatedUserId").(uuid.UUID)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected error, try again later",
+		})
+		return
+	}
+	timeline, err := api.BidsService.Timeline(r.Context(), productId, userId)
+	if err != nil {
+		if errors.Is(err, services.ErrProductNotFound) {
+			jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+				"error": "product not found",
+			})
+			return
+		}
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "failed to load the auction timeline, try again later",
+		})
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusOK, timeline)
+}
+
+// requestedPageSize reads the limit query parameter, falling back to the
+// default page size when it is absent.
+func requestedPageSize(r *http.Request) (int32, bool) {
+	raw := r.URL.Query().Get("limit")
+	if raw == "" {
+		return services.DefaultBidPageSize, true
+	}
+	limit, err := strconv.Atoi(raw)
+	if err != nil || limit < 1 || limit > services.MaxBidPageSize {
+		return 0, false
+	}
+	return int32(limit), true
+}
@@ -0,0 +1,128 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"github.com/FelipePn10/Gobid/internal/jsonutils"
+	"github.com/FelipePn10/Gobid/internal/services"
+	"github.com/google/uuid"
+	"io"
+	"log/slog"
+	"net/http"
+)
+
+const maxIdempotencyKeyLength = 255
+
+// recordingWriter keeps a copy of the response so it can be replayed.
+type recordingWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (rw *recordingWriter) WriteHeader(status int) {
+	if rw.status == 0 {
+		rw.status = status
+	}
+	rw.ResponseWriter.WriteHeader(status)
+}
+
+func (rw *recordingWriter) Write(b []byte) (int, error) {
+	if rw.status == 0 {
+		rw.status = http.StatusOK
+	}
+	rw.body.Write(b)
+	return rw.ResponseWriter.Write(b)
+}
+
+func (rw *recordingWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+// IdempotencyMiddleware makes requests carrying an Idempotency-Key header
+// safe to retry: the first response for a key is stored and returned again
+// for any retry of the same request, without running the handler twice.
+func (api *Api) IdempotencyMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get("Idempotency-Key")
+		if key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+				"error": "the idempotency key cannot be longer than 255 characters",
+			})
+			return
+		}
+		userId, ok := api.Sessions.Get(r.Context(), "AuthenticatedUserId").(uuid.UUID)
+		if !ok {
+			jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+				"error": "unexpected error, try again later",
+			})
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+				"error": "failed to read request body",
+			})
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		requestHash := sha256.Sum256([]byte(r.Method + " " + r.URL.RequestURI() + "\n" + string(body)))
+
+		stored, err := api.IdempotencyService.Begin(r.Context(), userId, key, requestHash[:])
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrIdempotencyKeyReused):
+				jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{
+					"error": err.Error(),
+				})
+			case errors.Is(err, services.ErrIdempotentRequestPending):
+				jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{
+					"error": err.Error(),
+				})
+			default:
+				jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+					"error": "unexpected error, try again later",
+				})
+			}
+			return
+		}
+		if stored != nil {
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			w.Header().Set("Idempotent-Replayed", "true")
+			w.WriteHeader(stored.StatusCode)
+			w.Write(stored.Body)
+			return
+		}
+
+		rec := &recordingWriter{ResponseWriter: w}
+		completed := false
+		defer func() {
+			if completed {
+				return
+			}
+			// Server errors and panics leave the key free for a retry.
+			if err := api.IdempotencyService.Release(context.WithoutCancel(r.Context()), userId, key); err != nil {
+				slog.Error("Failed to release idempotency key", "user_id", userId, "error", err)
+			}
+		}()
+		next.ServeHTTP(rec, r)
+
+		if rec.status != 0 && rec.status < http.StatusInternalServerError {
+			err := api.IdempotencyService.Complete(context.WithoutCancel(r.Context()), userId, key, services.StoredResponse{
+				StatusCode: rec.status,
+				Body:       rec.body.Bytes(),
+			})
+			if err != nil {
+				slog.Error("Failed to store idempotent response", "user_id", userId, "error", err)
+				return
+			}
+			completed = true
+		}
+	})
+}
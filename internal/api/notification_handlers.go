@@ -0,0 +1,46 @@
+package api
+
+import (
+	"github.com/FelipePn10/Gobid/internal/jsonutils"
+	"github.com/google/uuid"
+	"net/http"
+)
+
+func (api *Api) handleListNotifications(w http.ResponseWriter, r *http.Request) {
+	userId, ok := api.Sessions.Get(r.Context(), "AuthenticatedUserId").(uuid.UUID)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected error, try again later",
+		})
+		return
+	}
+	notifications, err := api.NotificationService.ListNotifications(r.Context(), userId)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "failed to list notifications, try again later",
+		})
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{
+		"notifications": notifications,
+	})
+}
+
+func (api *Api) handleMarkNotificationsRead(w http.ResponseWriter, r *http.Request) {
+	userId, ok := api.Sessions.Get(r.Context(), "AuthenticatedUserId").(uuid.UUID)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected error, try again later",
+		})
+		return
+	}
+	if err := api.NotificationService.MarkNotificationsRead(r.Context(), userId); err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "failed to update notifications, try again later",
+		})
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{
+		"message": "notifications marked as read",
+	})
+}
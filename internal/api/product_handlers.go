@@ -2,12 +2,15 @@ package api
 
 import (
 	"context"
+	"errors"
 	"github.com/FelipePn10/Gobid/internal/jsonutils"
 	"github.com/FelipePn10/Gobid/internal/services"
+	"github.com/FelipePn10/Gobid/internal/store/pgstore"
 	"github.com/FelipePn10/Gobid/internal/usecase/product"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"net/http"
+	"time"
 )
 
 func (api *Api) handleCreateProduct(w http.ResponseWriter, r *http.Request) {
@@ -16,6 +19,7 @@ func (api *Api) handleCreateProduct(w http.ResponseWriter, r *http.Request) {
 		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
 		return
 	}
+	data = data.Priced()
 	userID, ok := api.Sessions.Get(r.Context(), "AuthenticatedUserId").(uuid.UUID)
 	if !ok {
 		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
@@ -23,32 +27,77 @@ func (api *Api) handleCreateProduct(w http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
+	settings := services.AuctionSettings{
+		Type:               services.AuctionEnglish,
+		Currency:           data.ListingCurrency(),
+		Start:              time.Now(),
+		SoftCloseWindow:    time.Duration(data.SoftCloseWindow) * time.Minute,
+		SoftCloseExtension: time.Duration(data.SoftCloseExtension) * time.Minute,
+		Increment:          services.DefaultIncrementPolicy(data.ListingCurrency()),
+		ReservePrice:       data.ReservePrice.Amount,
+		BuyNowPrice:        data.BuyNowPrice.Amount,
+		Draft:              data.Draft,
+	}
+	if data.IncrementType != "" {
+		settings.Increment = services.IncrementPolicy{Type: data.IncrementType, Value: data.IncrementValue.Amount}
+	}
+	if data.AuctionStart != nil {
+		settings.Start = *data.AuctionStart
+	}
+	if data.AuctionType != "" {
+		settings.Type = data.AuctionType
+	}
+	if data.AuctionType == services.AuctionDutch {
+		settings.Dutch = services.DutchSchedule{
+			StartPrice: data.DutchStartPrice.Amount,
+			FloorPrice: data.DutchFloorPrice.Amount,
+			Step:       data.DutchPriceStep.Amount,
+			Interval:   time.Duration(data.DutchStepInterval) * time.Second,
+		}
+	}
 	productId, err := api.ProductService.CreateProduct(
 		r.Context(),
 		userID,
 		data.ProductName,
 		data.Description,
-		data.Baseprice,
+		data.Baseprice.Amount,
 		data.AuctionEnd,
+		settings,
+		data.Classification(),
 	)
 	if err != nil {
+		if errors.Is(err, services.ErrCategoryNotFound) {
+			jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{
+				"category_id": err.Error(),
+			})
+			return
+		}
 		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
 			"error": "failed to create product, try again later",
 		})
+		return
+	}
+	product, err := api.ProductService.GetProductByID(r.Context(), productId)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "failed to start auction, try again later",
+		})
+		return
 	}
 
-	ctx, _ := context.WithDeadline(context.Background(), data.AuctionEnd)
-	auctionRoom := services.NewAuctionRoom(ctx, productId, api.BidsService)
-
-	go auctionRoom.Run()
-
-	api.AuctionLobby.Lock()
-	api.AuctionLobby.Rooms[productId] = auctionRoom
-	api.AuctionLobby.Unlock()
-
-	jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
-		"message":    "auction has started with success",
-		"product_id": productId,
+	message := "product saved as a draft"
+	if product.Status != services.ProductDraft {
+		api.startAuctionRoom(product)
+		message = "auction has started with success"
+		if product.Status == services.ProductScheduled {
+			message = "auction has been scheduled with success"
+		}
+	}
+	jsonutils.EncodeJson(w, r, http.StatusCreated, map[string]any{
+		"message":       message,
+		"product_id":    productId,
+		"status":        product.Status,
+		"auction_start": product.AuctionStart,
 	})
 }
 
@@ -81,13 +130,51 @@ func (api *Api) handleUpdateProduct(w http.ResponseWriter, r *http.Request) {
 		data.Description,
 		data.Baseprice,
 		data.AuctionEnd,
+		data.ReservePrice,
+		data.BuyNowPrice,
+		data.Classification(),
 	)
 	if err != nil {
-		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
-			"error": "failed to update product, try again later",
-		})
+		var lockedErr *services.LockedFieldError
+		var fieldErr *services.ProductFieldError
+		switch {
+		case errors.Is(err, services.ErrCategoryNotFound):
+			jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{
+				"category_id": err.Error(),
+			})
+		case errors.Is(err, services.ErrProductNotFound):
+			jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+				"error": "product not found",
+			})
+		case errors.As(err, &fieldErr):
+			jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{
+				fieldErr.Field: fieldErr.Err.Error(),
+			})
+		case errors.As(err, &lockedErr):
+			jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{
+				"error": lockedErr.Error(),
+				"field": lockedErr.Field,
+			})
+		case errors.Is(err, services.ErrProductNotEditable):
+			jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{
+				"error": err.Error(),
+			})
+		default:
+			jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+				"error": "failed to update product, try again later",
+			})
+		}
 		return
 	}
+	if data.AuctionEnd != nil {
+		// Before the first bid the end may also move earlier, so the room
+		// takes the new deadline whichever way it moved.
+		api.publishAuctionEvent(r.Context(), productID, services.Message{
+			Kind:       services.AuctionRescheduled,
+			Message:    "the auction end has changed",
+			AuctionEnd: data.AuctionEnd,
+		})
+	}
 	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{
 		"message": "product updated successfully",
 	})
@@ -111,13 +198,135 @@ func (api *Api) handleDeleteProduct(w http.ResponseWriter, r *http.Request) {
 	}
 	err = api.ProductService.DeleteProduct(r.Context(), productID, userID)
 	if err != nil {
-		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
-			"error": "failed to delete product, try again later",
-		})
+		switch {
+		case errors.Is(err, services.ErrProductNotFound):
+			jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+				"error": "product not found",
+			})
+		case errors.Is(err, services.ErrAuctionHasBids):
+			jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{
+				"error": "the auction already has bids, cancel it instead",
+			})
+		case errors.Is(err, services.ErrProductNotEditable):
+			jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{
+				"error": err.Error(),
+			})
+		default:
+			jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+				"error": "failed to delete product, try again later",
+			})
+		}
 		return
 	}
+	api.publishAuctionEvent(r.Context(), productID, services.Message{
+		Kind:    services.AuctionCancelled,
+		Message: "the auction was withdrawn by the seller",
+	})
 	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{
 		"message": "product deleted successfully",
 	})
 
 }
+
+func (api *Api) handlePublishProduct(w http.ResponseWriter, r *http.Request) {
+	product, ok := api.transitionProduct(w, r, api.ProductService.PublishProduct)
+	if !ok {
+		return
+	}
+	api.startAuctionRoom(product)
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{
+		"message":       "product published successfully",
+		"status":        product.Status,
+		"auction_start": product.AuctionStart,
+	})
+}
+
+func (api *Api) handleCancelProduct(w http.ResponseWriter, r *http.Request) {
+	product, ok := api.transitionProduct(w, r, api.ProductService.CancelProduct)
+	if !ok {
+		return
+	}
+	api.publishAuctionEvent(r.Context(), product.ID, services.Message{
+		Kind:    services.AuctionCancelled,
+		Message: "the auction was cancelled by the seller",
+	})
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{
+		"message": "auction cancelled successfully",
+		"status":  product.Status,
+	})
+}
+
+func (api *Api) handleMarkProductPaid(w http.ResponseWriter, r *http.Request) {
+	product, ok := api.transitionProduct(w, r, api.ProductService.MarkProductPaid)
+	if !ok {
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{
+		"message": "product marked as paid",
+		"status":  product.Status,
+	})
+}
+
+// transitionProduct runs a status change of the authenticated seller's
+// product and writes the error response when it fails.
+func (api *Api) transitionProduct(
+	w http.ResponseWriter,
+	r *http.Request,
+	transition func(context.Context, uuid.UUID, uuid.UUID) (pgstore.Product, error),
+) (pgstore.Product, bool) {
+	productID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "invalid product ID format",
+		})
+		return pgstore.Product{}, false
+	}
+	userID, ok := api.Sessions.Get(r.Context(), "AuthenticatedUserId").(uuid.UUID)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected error, try again later",
+		})
+		return pgstore.Product{}, false
+	}
+	product, err := transition(r.Context(), productID, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrProductNotFound):
+			jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+				"error": "product not found",
+			})
+		case errors.Is(err, services.ErrInvalidStatusTransition), errors.Is(err, services.ErrAuctionHasEnded):
+			jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{
+				"error": err.Error(),
+			})
+		default:
+			jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+				"error": "failed to update product status, try again later",
+			})
+		}
+		return pgstore.Product{}, false
+	}
+	return product, true
+}
+
+func (api *Api) handleListProducts(w http.ResponseWriter, r *http.Request) {
+	data := product.ParseListProductsReq(r.URL.Query())
+	if problems := data.Valid(r.Context()); len(problems) > 0 {
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+	page, err := api.ProductService.ListProducts(r.Context(), data.Filter(), data.Sort, data.Cursor, data.Limit)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidCursor) {
+			jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+				"error": err.Error(),
+			})
+			return
+		}
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "failed to list products, try again later",
+		})
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusOK, page)
+}
@@ -0,0 +1,171 @@
+package api
+
+import (
+	"errors"
+	"github.com/FelipePn10/Gobid/internal/jsonutils"
+	"github.com/FelipePn10/Gobid/internal/services"
+	"github.com/FelipePn10/Gobid/internal/usecase/product"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"io"
+	"net/http"
+)
+
+// multipartOverhead leaves room for the form boundaries and headers around
+// the image itself.
+const multipartOverhead = 1 << 20
+
+func (api *Api) handleUploadProductImage(w http.ResponseWriter, r *http.Request) {
+	productId, err := uuid.Parse(chi.URLParam(r, "product_id"))
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "invalid product id - must be a valid uuid",
+		})
+		return
+	}
+	userId, ok := api.Sessions.Get(r.Context(), "AuthenticatedUserId").(uuid.UUID)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected error, try again later",
+		})
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, services.MaxImageBytes+multipartOverhead)
+	file, _, err := r.FormFile("image")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			jsonutils.EncodeJson(w, r, http.StatusRequestEntityTooLarge, map[string]any{
+				"error": "the image cannot be larger than 10 MB",
+			})
+			return
+		}
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "expected a multipart form with an image field",
+		})
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(io.LimitReader(file, services.MaxImageBytes+1))
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "failed to read the image",
+		})
+		return
+	}
+
+	image, err := api.ProductService.AddProductImage(r.Context(), productId, userId, data)
+	if err != nil {
+		encodeProductImageError(w, r, err, "failed to upload image, try again later")
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusCreated, image)
+}
+
+func (api *Api) handleListProductImages(w http.ResponseWriter, r *http.Request) {
+	productId, err := uuid.Parse(chi.URLParam(r, "product_id"))
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "invalid product id - must be a valid uuid",
+		})
+		return
+	}
+	// Anyone may list the images of a public listing; the session only
+	// matters for a seller looking at their own draft.
+	viewerId, _ := api.Sessions.Get(r.Context(), "AuthenticatedUserId").(uuid.UUID)
+	images, err := api.ProductService.ListProductImages(r.Context(), productId, viewerId)
+	if err != nil {
+		encodeProductImageError(w, r, err, "failed to list images, try again later")
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{
+		"images": images,
+	})
+}
+
+func (api *Api) handleDeleteProductImage(w http.ResponseWriter, r *http.Request) {
+	productId, err := uuid.Parse(chi.URLParam(r, "product_id"))
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "invalid product id - must be a valid uuid",
+		})
+		return
+	}
+	imageId, err := uuid.Parse(chi.URLParam(r, "image_id"))
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "invalid image id - must be a valid uuid",
+		})
+		return
+	}
+	userId, ok := api.Sessions.Get(r.Context(), "AuthenticatedUserId").(uuid.UUID)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected error, try again later",
+		})
+		return
+	}
+	if err := api.ProductService.DeleteProductImage(r.Context(), productId, userId, imageId); err != nil {
+		encodeProductImageError(w, r, err, "failed to delete image, try again later")
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{
+		"message": "image deleted successfully",
+	})
+}
+
+func (api *Api) handleReorderProductImages(w http.ResponseWriter, r *http.Request) {
+	productId, err := uuid.Parse(chi.URLParam(r, "product_id"))
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "invalid product id - must be a valid uuid",
+		})
+		return
+	}
+	data, problems, err := jsonutils.DecodeValidJson[product.ReorderImagesReq](r)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+	userId, ok := api.Sessions.Get(r.Context(), "AuthenticatedUserId").(uuid.UUID)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected error, try again later",
+		})
+		return
+	}
+	images, err := api.ProductService.ReorderProductImages(r.Context(), productId, userId, data.ImageIDs)
+	if err != nil {
+		encodeProductImageError(w, r, err, "failed to reorder images, try again later")
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{
+		"images": images,
+	})
+}
+
+func encodeProductImageError(w http.ResponseWriter, r *http.Request, err error, fallback string) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, services.ErrProductNotFound), errors.Is(err, services.ErrImageNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, services.ErrUnsupportedImage):
+		status = http.StatusUnsupportedMediaType
+	case errors.Is(err, services.ErrImageTooLarge):
+		status = http.StatusRequestEntityTooLarge
+	case errors.Is(err, services.ErrTooManyImages):
+		status = http.StatusConflict
+	case errors.Is(err, services.ErrInvalidImageOrder):
+		status = http.StatusUnprocessableEntity
+	case errors.Is(err, services.ErrImageStorageDisabled):
+		status = http.StatusServiceUnavailable
+	default:
+		jsonutils.EncodeJson(w, r, status, map[string]any{
+			"error": fallback,
+		})
+		return
+	}
+	jsonutils.EncodeJson(w, r, status, map[string]any{
+		"error": err.Error(),
+	})
+}
@@ -1,10 +1,13 @@
 package api
 
 import (
+	"github.com/FelipePn10/Gobid/internal/services"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/gorilla/csrf"
+	"net/http"
 	"os"
+	"strings"
 )
 
 func (api *Api) BindRoutes() {
@@ -16,6 +19,11 @@ func (api *Api) BindRoutes() {
 	)
 	api.Router.Use(csrfMiddleware)
 
+	// A local blob store with a path as its base URL serves its own files.
+	if local, ok := api.ProductService.Blobs.(*services.LocalBlobStore); ok && strings.HasPrefix(local.BaseURL, "/") {
+		api.Router.Handle(local.BaseURL+"/*", http.StripPrefix(local.BaseURL+"/", local))
+	}
+
 	api.Router.Route("/api", func(r chi.Router) {
 		r.Route("/v1", func(r chi.Router) {
 			r.Get("/csrftoken", api.HandleGetCSRFtoken)
@@ -28,14 +36,57 @@ func (api *Api) BindRoutes() {
 				})
 			})
 			r.Route("/products", func(r chi.Router) {
+				r.Get("/", api.handleListProducts)
+				r.Get("/{product_id}/images", api.handleListProductImages)
 				r.Group(func(r chi.Router) {
 					r.Use(api.AuthMiddleware)
 					r.Post("/", api.handleCreateProduct)
 					r.Put("/{id}", api.handleUpdateProduct)
 					r.Delete("/{id}", api.handleDeleteProduct)
+					r.Post("/{id}/publish", api.handlePublishProduct)
+					r.Post("/{id}/cancel", api.handleCancelProduct)
+					r.Post("/{id}/mark-paid", api.handleMarkProductPaid)
 					r.Get("/ws/subscribe/{product_id}", api.handlerSubscribeUsertoAuction)
+					r.Get("/{product_id}/events", api.handleAuctionEvents)
+					r.Get("/{product_id}/bids", api.handleListBids)
+					r.With(api.IdempotencyMiddleware).Post("/{product_id}/bids", api.handlePlaceBid)
+					r.Get("/{product_id}/timeline", api.handleAuctionTimeline)
+					r.Post("/{product_id}/max-bids", api.handlePlaceMaxBid)
+					r.Post("/{product_id}/buy-now", api.handleBuyNow)
+					r.Post("/{product_id}/images", api.handleUploadProductImage)
+					r.Put("/{product_id}/images/order", api.handleReorderProductImages)
+					r.Delete("/{product_id}/images/{image_id}", api.handleDeleteProductImage)
+				})
+			})
+			r.Route("/categories", func(r chi.Router) {
+				r.Get("/", api.handleListCategories)
+				r.Group(func(r chi.Router) {
+					r.Use(api.AuthMiddleware)
+					r.Put("/{id}/subscription", api.handleCategorySubscription)
+					r.Delete("/{id}/subscription", api.handleCategorySubscription)
 				})
 			})
+			r.Route("/tags", func(r chi.Router) {
+				r.Get("/", api.handleListTags)
+				r.Group(func(r chi.Router) {
+					r.Use(api.AuthMiddleware)
+					r.Put("/{name}/subscription", api.handleTagSubscription)
+					r.Delete("/{name}/subscription", api.handleTagSubscription)
+				})
+			})
+			r.Route("/notifications", func(r chi.Router) {
+				r.Use(api.AuthMiddleware)
+				r.Get("/", api.handleListNotifications)
+				r.Post("/read", api.handleMarkNotificationsRead)
+			})
+			r.Route("/admin", func(r chi.Router) {
+				r.Use(api.AuthMiddleware, api.AdminMiddleware)
+				r.Post("/categories", api.handleCreateCategory)
+				r.Patch("/categories/{id}", api.handleUpdateCategory)
+				r.Delete("/categories/{id}", api.handleDeleteCategory)
+				r.Patch("/tags/{id}", api.handleRenameTag)
+				r.Delete("/tags/{id}", api.handleDeleteTag)
+			})
 		})
 	})
 }
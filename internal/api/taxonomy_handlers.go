@@ -0,0 +1,231 @@
+package api
+
+import (
+	"errors"
+	"github.com/FelipePn10/Gobid/internal/jsonutils"
+	"github.com/FelipePn10/Gobid/internal/services"
+	"github.com/FelipePn10/Gobid/internal/usecase/taxonomy"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"net/http"
+)
+
+func (api *Api) handleListCategories(w http.ResponseWriter, r *http.Request) {
+	tree, err := api.TaxonomyService.CategoryTree(r.Context())
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "failed to list categories, try again later",
+		})
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{
+		"categories": tree,
+	})
+}
+
+func (api *Api) handleCreateCategory(w http.ResponseWriter, r *http.Request) {
+	data, problems, err := jsonutils.DecodeValidJson[taxonomy.CreateCategoryReq](r)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+	category, err := api.TaxonomyService.CreateCategory(r.Context(), data.ParentID, data.Name, data.Slug)
+	if err != nil {
+		encodeCategoryError(w, r, err, "failed to create category, try again later")
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusCreated, category)
+}
+
+func (api *Api) handleUpdateCategory(w http.ResponseWriter, r *http.Request) {
+	categoryId, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "invalid category id - must be a valid uuid",
+		})
+		return
+	}
+	data, problems, err := jsonutils.DecodeValidJson[taxonomy.UpdateCategoryReq](r)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+	move, parentId, _ := data.Parent()
+	category, err := api.TaxonomyService.UpdateCategory(r.Context(), categoryId, data.Name, data.Slug, move, parentId)
+	if err != nil {
+		encodeCategoryError(w, r, err, "failed to update category, try again later")
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusOK, category)
+}
+
+func (api *Api) handleDeleteCategory(w http.ResponseWriter, r *http.Request) {
+	categoryId, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "invalid category id - must be a valid uuid",
+		})
+		return
+	}
+	if err := api.TaxonomyService.DeleteCategory(r.Context(), categoryId); err != nil {
+		encodeCategoryError(w, r, err, "failed to delete category, try again later")
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{
+		"message": "category deleted successfully",
+	})
+}
+
+func encodeCategoryError(w http.ResponseWriter, r *http.Request, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrCategoryNotFound):
+		jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+			"error": err.Error(),
+		})
+	case errors.Is(err, services.ErrCategorySlugTaken), errors.Is(err, services.ErrCategoryCycle), errors.Is(err, services.ErrCategoryInUse):
+		jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{
+			"error": err.Error(),
+		})
+	default:
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": fallback,
+		})
+	}
+}
+
+func (api *Api) handleListTags(w http.ResponseWriter, r *http.Request) {
+	tags, err := api.TaxonomyService.ListTags(r.Context())
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "failed to list tags, try again later",
+		})
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{
+		"tags": tags,
+	})
+}
+
+func (api *Api) handleRenameTag(w http.ResponseWriter, r *http.Request) {
+	tagId, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "invalid tag id - must be a valid uuid",
+		})
+		return
+	}
+	data, problems, err := jsonutils.DecodeValidJson[taxonomy.RenameTagReq](r)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+	tag, err := api.TaxonomyService.RenameTag(r.Context(), tagId, data.Name)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrTagNotFound):
+			jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+				"error": err.Error(),
+			})
+		case errors.Is(err, services.ErrTagExists):
+			jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{
+				"error": err.Error(),
+			})
+		default:
+			jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+				"error": "failed to rename tag, try again later",
+			})
+		}
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusOK, tag)
+}
+
+func (api *Api) handleDeleteTag(w http.ResponseWriter, r *http.Request) {
+	tagId, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "invalid tag id - must be a valid uuid",
+		})
+		return
+	}
+	if err := api.TaxonomyService.DeleteTag(r.Context(), tagId); err != nil {
+		if errors.Is(err, services.ErrTagNotFound) {
+			jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+				"error": err.Error(),
+			})
+			return
+		}
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "failed to delete tag, try again later",
+		})
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{
+		"message": "tag deleted successfully",
+	})
+}
+
+func (api *Api) handleCategorySubscription(w http.ResponseWriter, r *http.Request) {
+	categoryId, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{
+			"error": "invalid category id - must be a valid uuid",
+		})
+		return
+	}
+	userId, ok := api.Sessions.Get(r.Context(), "AuthenticatedUserId").(uuid.UUID)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected error, try again later",
+		})
+		return
+	}
+	message := "subscribed to category"
+	if r.Method == http.MethodDelete {
+		err = api.TaxonomyService.UnsubscribeFromCategory(r.Context(), userId, categoryId)
+		message = "unsubscribed from category"
+	} else {
+		err = api.TaxonomyService.SubscribeToCategory(r.Context(), userId, categoryId)
+	}
+	if err != nil {
+		encodeCategoryError(w, r, err, "failed to update subscription, try again later")
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{
+		"message": message,
+	})
+}
+
+func (api *Api) handleTagSubscription(w http.ResponseWriter, r *http.Request) {
+	userId, ok := api.Sessions.Get(r.Context(), "AuthenticatedUserId").(uuid.UUID)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "unexpected error, try again later",
+		})
+		return
+	}
+	var err error
+	tag := chi.URLParam(r, "name")
+	message := "subscribed to tag"
+	if r.Method == http.MethodDelete {
+		err = api.TaxonomyService.UnsubscribeFromTag(r.Context(), userId, tag)
+		message = "unsubscribed from tag"
+	} else {
+		err = api.TaxonomyService.SubscribeToTag(r.Context(), userId, tag)
+	}
+	if err != nil {
+		if errors.Is(err, services.ErrTagNotFound) {
+			jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{
+				"error": err.Error(),
+			})
+			return
+		}
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{
+			"error": "failed to update subscription, try again later",
+		})
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{
+		"message": message,
+	})
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
@@ -19,94 +20,519 @@ const (
 	NewBidPlaced
 	AuctionFinished
 	InvalidJSON
+	AuctionExtended
+	BuyNow
+	SoldViaBuyNow
+	FailedToBuyNow
+	PriceDropped
+	AcceptPrice
+	PriceAccepted
+	FailedToAcceptPrice
+	AuctionCountdown
+	AuctionStarted
+	ReplayIncomplete
+	RoomSnapshot
+	AuctionCancelled
+	// AuctionRescheduled pushes the end of an auction later, which the
+	// seller can only do before the first bid.
+	AuctionRescheduled
 )
 
 const (
-	maxMessageSize = 512
-	readDeadline   = 60 * time.Second
-	pingPeriod     = (readDeadline * 9) / 10
-	writeWait      = 10 * time.Second
+	maxMessageSize    = 512
+	readDeadline      = 60 * time.Second
+	pingPeriod        = (readDeadline * 9) / 10
+	writeWait         = 10 * time.Second
+	settlementTimeout = 30 * time.Second
+	// A room retries a failed settlement with doubling delays before leaving
+	// it to the settlement sweeper.
+	settlementRetryDelay  = 5 * time.Second
+	maxSettlementAttempts = 4
+	publishTimeout        = 5 * time.Second
+	countdownInterval     = time.Minute
 )
 
+// Seq numbers the events a room delivers to all of its clients, in order,
+// and Cursor is what a client resumes from after reconnecting. Replies meant
+// for a single client carry neither. UserId never reaches clients: they get
+// the user's pseudonym as Bidder, and Mine tells them when it is themselves.
 type Message struct {
-	Message string      `json:"message,omitempty"`
-	Amount  float64     `json:"amount,omitempty"`
-	Kind    MessageKind `json:"kind"`
-	UserId  uuid.UUID   `json:"userId,omitempty"`
+	Seq        uint64           `json:"seq,omitempty"`
+	Cursor     string           `json:"cursor,omitempty"`
+	Message    string           `json:"message,omitempty"`
+	Amount     *Money           `json:"amount,omitempty"`
+	Converted  *Money           `json:"convertedAmount,omitempty"`
+	MaxAmount  *Money           `json:"maxAmount,omitempty"`
+	Kind       MessageKind      `json:"kind"`
+	UserId     uuid.UUID        `json:"-"`
+	Bidder     string           `json:"bidder,omitempty"`
+	Mine       bool             `json:"mine,omitempty"`
+	AuctionEnd *time.Time       `json:"auctionEnd,omitempty"`
+	ReserveMet *bool            `json:"reserveMet,omitempty"`
+	StartsAt   *time.Time       `json:"startsAt,omitempty"`
+	Remaining  int64            `json:"remainingSeconds,omitempty"`
+	Automatic  bool             `json:"automatic,omitempty"`
+	Snapshot   *AuctionSnapshot `json:"snapshot,omitempty"`
+
+	reply chan<- Message
+	// sender is the connection a message was read from.
+	sender *Client
+	err    error
 }
 
+var (
+	ErrAuctionHasEnded   = errors.New("the auction has ended")
+	ErrAuctionNotStarted = errors.New("the auction has not started yet")
+)
+
 type AuctionLobby struct {
 	sync.Mutex
 	Rooms map[uuid.UUID]*AuctionRoom
 }
 
+// AuctionRoom runs one auction. Clients holds every open connection, so a
+// user watching from several tabs gets each event in every one of them.
 type AuctionRoom struct {
 	Id          uuid.UUID
 	Context     context.Context
 	Broadcast   chan Message
 	Register    chan *Client
 	Unregister  chan *Client
-	Clients     map[uuid.UUID]*Client
+	Clients     map[*Client]struct{}
 	BidsService *BidsService
+	Dutch       *DutchSchedule
+	StartsAt    time.Time
+	Currency    string
+	Rates       ExchangeRateProvider
+	Broadcaster Broadcaster
+	Elector     SettlementElector
+
+	currentPrice   int64
+	awaitingResult bool
+	settleAttempts int
+	epoch          string
+	seq            uint64
+	history        *eventLog
+	cancel         context.CancelFunc
+	done           chan struct{}
 }
 
 type Client struct {
-	Room   *AuctionRoom
-	Conn   *websocket.Conn
-	Send   chan Message
-	UserId uuid.UUID
+	Room            *AuctionRoom
+	Conn            *websocket.Conn
+	Send            chan Message
+	UserId          uuid.UUID
+	DisplayCurrency string
+	// LastEvent is the last event a reconnecting client saw.
+	LastEvent *EventCursor
 }
 
 func (r *AuctionRoom) registerClient(c *Client) {
 	slog.Info("New user Connected", "Client", c)
-	r.Clients[c.UserId] = c
+	r.Clients[c] = struct{}{}
+	if c.LastEvent != nil {
+		r.replay(c)
+	}
+	if snapshot, err := r.snapshot(); err != nil {
+		slog.Error("Failed to build room snapshot", "auctionID", r.Id, "error", err)
+	} else {
+		r.send(c, snapshot)
+	}
+	if time.Now().Before(r.StartsAt) {
+		r.send(c, r.countdownMessage())
+	}
+}
+
+// replay sends c the events it missed while disconnected, warning it when
+// some of them are no longer buffered.
+func (r *AuctionRoom) replay(c *Client) {
+	lastSeq := c.LastEvent.Seq
+	// A cursor from another run of the room, on another instance or from
+	// before a restart, cannot tell which of this run's events were missed.
+	// A bare sequence number is taken to be from this run unless this run
+	// has not got that far.
+	otherRun := c.LastEvent.Epoch != r.epoch
+	if c.LastEvent.Epoch == "" {
+		otherRun = lastSeq > r.seq
+	}
+	if otherRun {
+		lastSeq = 0
+	}
+	missed, complete := r.history.since(lastSeq)
+	if otherRun || !complete {
+		r.send(c, Message{Kind: ReplayIncomplete, Message: "some events are no longer available, reload the auction"})
+	}
+	for _, m := range missed {
+		if !m.hiddenFrom(c.UserId) {
+			r.send(c, m)
+		}
+	}
+}
+
+func (r *AuctionRoom) countdownMessage() Message {
+	return Message{
+		Kind:      AuctionCountdown,
+		Message:   "the auction has not started yet",
+		StartsAt:  &r.StartsAt,
+		Remaining: int64(time.Until(r.StartsAt).Seconds()),
+	}
+}
+
+func (r *AuctionRoom) startAuction() {
+	slog.Info("Auction has started", "auctionID", r.Id)
+	r.deliver(Message{Kind: AuctionStarted, Message: "the auction has started", StartsAt: &r.StartsAt})
 }
 
 func (r *AuctionRoom) unregisterClient(c *Client) {
 	slog.Info("User disconnected", "Client", c)
-	delete(r.Clients, c.UserId)
-	r.Clients[c.UserId] = c
+	delete(r.Clients, c)
 }
 
 func (r *AuctionRoom) broadcastMessage(m Message) {
 	slog.Info("New message recieved", "RoomID", r.Id, "message", m.Message, "user_id", m.UserId)
 	switch m.Kind {
 	case PlaceBid:
-		bid, err := r.BidsService.Placebid(r.Context, r.Id, m.UserId, m.Amount)
-		if err != nil {
-			if errors.Is(err, ErrBidIsTooLow) {
-				if client, ok := r.Clients[m.UserId]; ok {
-					client.Send <- Message{Kind: FailedToPlaceBid, Message: ErrBidIsTooLow.Error(), UserId: m.UserId}
-				}
-				return
-			}
+		r.placeBid(m)
+	case BuyNow:
+		r.buyNow(m)
+	case AcceptPrice:
+		r.acceptPrice(m)
+	case InvalidJSON:
+		r.reply(m, m)
+	}
+}
+
+// deliver hands m to the clients connected to this room. Events every room
+// derives on its own, such as countdowns and dutch price drops, go straight
+// here; everything else is published and delivered when it comes back through
+// the broadcaster.
+func (r *AuctionRoom) deliver(m Message) {
+	r.seq++
+	m.Seq = r.seq
+	m.Cursor = r.cursor().String()
+	r.history.add(m)
+	for client := range r.Clients {
+		if !m.hiddenFrom(client.UserId) {
+			r.send(client, m)
+		}
+	}
+}
+
+// hiddenFrom reports whether userId already got m as a direct reply, like
+// the confirmation of its own bid.
+func (m Message) hiddenFrom(userId uuid.UUID) bool {
+	return m.Kind == NewBidPlaced && !m.Automatic && m.UserId == userId
+}
+
+func (r *AuctionRoom) publish(m Message) error {
+	ctx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+	defer cancel()
+	err := r.Broadcaster.Publish(ctx, r.Id, m)
+	if err != nil {
+		slog.Error("Failed to publish auction event", "auctionID", r.Id, "kind", m.Kind, "error", err)
+	}
+	return err
+}
+
+// broadcast publishes m, handling it right away if the broadcaster could not
+// take it so this instance's clients still see it.
+func (r *AuctionRoom) broadcast(m Message) {
+	if err := r.publish(m); err != nil {
+		r.handleEvent(m)
+	}
+}
+
+// handleEvent applies an event received from the broadcaster.
+func (r *AuctionRoom) handleEvent(m Message) {
+	switch m.Kind {
+	case AuctionExtended:
+		if m.AuctionEnd == nil {
+			return
 		}
-		if client, ok := r.Clients[m.UserId]; ok {
-			client.Send <- Message{Kind: SuccessFullyPlaceBid, Message: "Your bid was successfully placed.", UserId: m.UserId}
+		if deadline, ok := r.Context.Deadline(); ok && !m.AuctionEnd.After(deadline) {
+			return
 		}
-		for id, client := range r.Clients {
-			newBidMessage := Message{Kind: NewBidPlaced, Message: "A new bid was placed.", Amount: bid.BidAmount, UserId: m.UserId}
-			if id == m.UserId {
-				continue
-			}
-			client.Send <- newBidMessage
+		r.moveDeadline(AuctionExtended, "the auction has been extended", *m.AuctionEnd)
+	case AuctionRescheduled:
+		if m.AuctionEnd == nil {
+			return
 		}
-	case InvalidJSON:
-		client, ok := r.Clients[m.UserId]
-		if !ok {
-			slog.Info("Client not found in hashmap", "user_id", m.UserId)
+		if deadline, ok := r.Context.Deadline(); ok && m.AuctionEnd.Equal(deadline) {
 			return
 		}
-		client.Send <- m
+		r.moveDeadline(AuctionRescheduled, "the auction end has changed", *m.AuctionEnd)
+	case SoldViaBuyNow, PriceAccepted, AuctionFinished, AuctionCancelled:
+		r.deliver(m)
+		r.cancel()
+	default:
+		r.deliver(m)
 	}
 }
+
+// send delivers m to c, adding the amount in the client's display currency
+// and naming the user it is about by their pseudonym.
+func (r *AuctionRoom) send(c *Client, m Message) {
+	m.Converted = DisplayAmount(context.Background(), r.Rates, m.Amount, c.DisplayCurrency)
+	if m.UserId != uuid.Nil {
+		m.Bidder = BidderPseudonym(r.Id, m.UserId)
+		m.Mine = m.UserId == c.UserId
+	}
+	c.Send <- m
+}
+
+func (r *AuctionRoom) money(amount int64) *Money {
+	return NewMoney(amount, r.Currency)
+}
+
+func (r *AuctionRoom) placeBid(m Message) {
+	var placed PlacedBid
+	var err error
+	switch {
+	case m.MaxAmount != nil:
+		placed, err = r.BidsService.PlaceMaxBid(r.Context, r.Id, m.UserId, *m.MaxAmount)
+	case m.Amount != nil:
+		placed, err = r.BidsService.Placebid(r.Context, r.Id, m.UserId, *m.Amount)
+	default:
+		err = ErrInvalidAmount
+	}
+	if err != nil {
+		reply := Message{Kind: FailedToPlaceBid, Message: "Your bid could not be placed, try again later.", UserId: m.UserId, err: err}
+		var incrementErr *BidBelowIncrementError
+		switch {
+		case errors.Is(err, ErrBidIsTooLow), errors.Is(err, ErrWrongAuctionType), errors.Is(err, ErrAuctionHasEnded), errors.Is(err, ErrAuctionNotStarted),
+			errors.Is(err, ErrInvalidAmount), errors.Is(err, ErrCurrencyMismatch):
+			reply.Message = err.Error()
+		case errors.As(err, &incrementErr):
+			reply.Message = incrementErr.Error()
+			reply.Amount = &incrementErr.NextMinimum
+		default:
+			slog.Error("Failed to place bid", "RoomID", r.Id, "user_id", m.UserId, "error", err)
+		}
+		r.reply(m, reply)
+		return
+	}
+
+	if placed.Sealed {
+		r.reply(m, Message{Kind: SuccessFullyPlaceBid, Message: "Your sealed bid was recorded.", Amount: r.money(placed.Bid.BidAmount), UserId: m.UserId})
+		return
+	}
+	if m.MaxAmount != nil {
+		r.reply(m, Message{Kind: SuccessFullyPlaceBid, Message: "Your maximum bid was registered.", MaxAmount: r.money(m.MaxAmount.Amount), UserId: m.UserId})
+	} else {
+		r.reply(m, Message{Kind: SuccessFullyPlaceBid, Message: "Your bid was successfully placed.", Amount: r.money(placed.Bid.BidAmount), UserId: m.UserId, ReserveMet: placed.ReserveMet})
+		r.broadcast(Message{Kind: NewBidPlaced, Message: "A new bid was placed.", Amount: r.money(placed.Bid.BidAmount), UserId: m.UserId, ReserveMet: placed.ReserveMet})
+	}
+	for _, bid := range placed.ProxyBids {
+		r.broadcast(Message{Kind: NewBidPlaced, Message: "An automatic bid was placed.", Amount: r.money(bid.BidAmount), UserId: bid.BidderID, ReserveMet: placed.ReserveMet, Automatic: true})
+	}
+	if placed.Extended {
+		// Extend before publishing so the old deadline cannot fire while the
+		// event makes its way back; the echo is then ignored.
+		r.moveDeadline(AuctionExtended, "the auction has been extended", placed.AuctionEnd)
+		r.publish(Message{Kind: AuctionExtended, Message: "the auction has been extended", AuctionEnd: &placed.AuctionEnd})
+	}
+}
+
+func (r *AuctionRoom) buyNow(m Message) {
+	result, err := r.BidsService.BuyNow(r.Context, r.Id, m.UserId)
+	if err != nil {
+		reply := Message{Kind: FailedToBuyNow, Message: "The product could not be bought, try again later.", UserId: m.UserId, err: err}
+		switch {
+		case errors.Is(err, ErrBuyNowUnavailable), errors.Is(err, ErrAuctionHasEnded), errors.Is(err, ErrAuctionNotStarted), errors.Is(err, ErrProductNotFound):
+			reply.Message = err.Error()
+		default:
+			slog.Error("Failed to buy product", "RoomID", r.Id, "user_id", m.UserId, "error", err)
+		}
+		r.reply(m, reply)
+		return
+	}
+
+	slog.Info("Product sold via buy now", "auctionID", r.Id, "buyer", result.WinnerID, "price", result.HammerPrice)
+	message := Message{Kind: SoldViaBuyNow, Message: "the product was sold at the buy now price", Amount: r.money(result.HammerPrice), UserId: result.WinnerID}
+	if m.reply != nil {
+		m.reply <- message
+	}
+	r.broadcast(message)
+}
+
+func (r *AuctionRoom) dropPrice() {
+	price := r.Dutch.PriceAt(time.Now())
+	if price >= r.currentPrice {
+		return
+	}
+	r.currentPrice = price
+	r.deliver(Message{Kind: PriceDropped, Message: "the price has dropped", Amount: r.money(price)})
+}
+
+func (r *AuctionRoom) acceptPrice(m Message) {
+	result, err := r.BidsService.AcceptDutchPrice(r.Context, r.Id, m.UserId)
+	if err != nil {
+		reply := Message{Kind: FailedToAcceptPrice, Message: "The price could not be accepted, try again later.", UserId: m.UserId, err: err}
+		switch {
+		case errors.Is(err, ErrWrongAuctionType), errors.Is(err, ErrAuctionHasEnded), errors.Is(err, ErrAuctionNotStarted), errors.Is(err, ErrProductNotFound):
+			reply.Message = err.Error()
+		default:
+			slog.Error("Failed to accept price", "RoomID", r.Id, "user_id", m.UserId, "error", err)
+		}
+		r.reply(m, reply)
+		return
+	}
+
+	slog.Info("Dutch auction won", "auctionID", r.Id, "winner", result.WinnerID, "price", result.HammerPrice)
+	message := Message{Kind: PriceAccepted, Message: "the price was accepted and the auction has been won", Amount: r.money(result.HammerPrice), UserId: result.WinnerID}
+	if m.reply != nil {
+		m.reply <- message
+	}
+	r.broadcast(message)
+}
+
+func (r *AuctionRoom) reply(m Message, reply Message) {
+	if m.reply != nil {
+		m.reply <- reply
+		return
+	}
+	if _, ok := r.Clients[m.sender]; ok {
+		r.send(m.sender, reply)
+	}
+}
+
+// Submit hands a message to the room loop and waits for the reply meant for
+// its sender, so callers outside a websocket connection get the same handling.
+func (r *AuctionRoom) Submit(ctx context.Context, m Message) (Message, error) {
+	reply := make(chan Message, 1)
+	m.reply = reply
+	select {
+	case r.Broadcast <- m:
+	case <-r.done:
+		return Message{}, ErrAuctionHasEnded
+	case <-ctx.Done():
+		return Message{}, ctx.Err()
+	}
+	// Once the room has the message it will be handled, so wait for the
+	// outcome even if the caller gives up; otherwise a retry could repeat it.
+	select {
+	case resp := <-reply:
+		return resp, resp.err
+	case <-r.done:
+		select {
+		case resp := <-reply:
+			return resp, resp.err
+		default:
+			return Message{}, ErrAuctionHasEnded
+		}
+	}
+}
+
+func (r *AuctionRoom) moveDeadline(kind MessageKind, message string, auctionEnd time.Time) {
+	ctx, cancel := context.WithDeadline(context.Background(), auctionEnd)
+	r.cancel()
+	r.Context, r.cancel = ctx, cancel
+
+	slog.Info("Auction end has moved", "auctionID", r.Id, "auction_end", auctionEnd)
+	r.deliver(Message{Kind: kind, Message: message, AuctionEnd: &auctionEnd})
+}
+
+// finishAuction settles the auction once its deadline passes and reports
+// whether the room can close. When another instance leads settlement the room
+// waits for the published result, settling itself only if none arrives in time.
+func (r *AuctionRoom) finishAuction() bool {
+	if errors.Is(r.Context.Err(), context.Canceled) {
+		slog.Info("Auction has ended.", "auctionID", r.Id)
+		return true
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), settlementTimeout)
+	defer cancel()
+
+	leader := r.Elector == nil || r.awaitingResult
+	if !leader {
+		release, led, err := r.Elector.LeadSettlement(ctx, r.Id)
+		switch {
+		case err != nil:
+			slog.Error("Failed to elect settlement leader", "auctionID", r.Id, "error", err)
+			leader = true
+		case led:
+			defer release()
+			leader = true
+		default:
+			r.awaitingResult = true
+			r.cancel()
+			r.Context, r.cancel = context.WithTimeout(context.Background(), settlementTimeout)
+			return false
+		}
+	}
+
+	message := Message{Kind: AuctionFinished, Message: "auction has been finished without a winner"}
+	result, err := r.BidsService.SettleAuction(ctx, r.Id)
+	var stillOpen *AuctionStillOpenError
+	if errors.As(err, &stillOpen) {
+		r.awaitingResult = false
+		r.moveDeadline(AuctionExtended, "the auction has been extended", stillOpen.AuctionEnd)
+		return false
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrAuctionCancelled):
+			message = Message{Kind: AuctionCancelled, Message: "the auction was cancelled by the seller"}
+		case errors.Is(err, ErrProductNotFound):
+			message = Message{Kind: AuctionCancelled, Message: "the auction was removed by the seller"}
+		case errors.Is(err, ErrReserveNotMet):
+			reserveMet := false
+			message.Message = "auction has been finished without meeting the reserve price"
+			message.ReserveMet = &reserveMet
+		case errors.Is(err, ErrAuctionHasNoBids):
+		case r.settleAttempts < maxSettlementAttempts:
+			r.settleAttempts++
+			delay := settlementRetryDelay << (r.settleAttempts - 1)
+			slog.Error("Failed to settle auction, retrying", "auctionID", r.Id, "attempt", r.settleAttempts, "retry_in", delay, "error", err)
+			r.cancel()
+			r.Context, r.cancel = context.WithTimeout(context.Background(), delay)
+			return false
+		default:
+			slog.Error("Failed to settle auction", "auctionID", r.Id, "error", err)
+			message.Message = "auction has ended, the result will be announced shortly"
+		}
+	}
+	slog.Info("Auction has ended.", "auctionID", r.Id)
+	if err == nil {
+		slog.Info("Auction settled", "auctionID", r.Id, "winner", result.WinnerID, "hammer_price", result.HammerPrice)
+		message = Message{
+			Kind:    AuctionFinished,
+			Message: "auction has been finished",
+			Amount:  r.money(result.HammerPrice),
+			UserId:  result.WinnerID,
+		}
+	}
+	// The room closes right away, so its own clients cannot wait for the echo.
+	r.deliver(message)
+	if !r.awaitingResult {
+		r.publish(message)
+	}
+	return true
+}
+
 func (r *AuctionRoom) Run() {
 	slog.Info("Auction has begun", "AuctionId", r.Id)
+	events, unsubscribe := r.Broadcaster.Subscribe(r.Id)
 	defer func() {
-		close(r.Broadcast)
-		close(r.Register)
-		close(r.Unregister)
+		unsubscribe()
+		r.cancel()
+		close(r.done)
 	}()
+	var startTimer, countdown <-chan time.Time
+	if wait := time.Until(r.StartsAt); wait > 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		ticker := time.NewTicker(countdownInterval)
+		defer ticker.Stop()
+		startTimer, countdown = timer.C, ticker.C
+	}
+	var priceDrops <-chan time.Time
+	if r.Dutch != nil {
+		ticker := time.NewTicker(r.Dutch.Interval)
+		defer ticker.Stop()
+		priceDrops = ticker.C
+		r.currentPrice = r.Dutch.PriceAt(time.Now())
+	}
 	for {
 		select {
 		case client := <-r.Register:
@@ -117,28 +543,50 @@ func (r *AuctionRoom) Run() {
 			r.unregisterClient(client)
 		case message := <-r.Broadcast:
 			r.broadcastMessage(message)
+		case <-countdown:
+			r.deliver(r.countdownMessage())
+		case <-startTimer:
+			countdown = nil
+			r.startAuction()
+		case <-priceDrops:
+			r.dropPrice()
+		case message := <-events:
+			r.handleEvent(message)
 		case <-r.Context.Done():
-			slog.Info("Auction has ended.", "auctionID", r.Id)
-			for _, client := range r.Clients {
-				client.Send <- Message{Kind: AuctionFinished, Message: "auction has been finished"}
+			if r.finishAuction() {
+				return
 			}
-			return
 		}
 	}
 }
 
 func NewAuctionRoom(ctx context.Context, id uuid.UUID, BidsService BidsService) *AuctionRoom {
+	ctx, cancel := context.WithCancel(ctx)
 	return &AuctionRoom{
 		Id:          id,
 		Context:     ctx,
+		cancel:      cancel,
 		Broadcast:   make(chan Message),
 		Register:    make(chan *Client),
 		Unregister:  make(chan *Client),
-		Clients:     make(map[uuid.UUID]*Client),
+		Clients:     make(map[*Client]struct{}),
 		BidsService: &BidsService,
+		Broadcaster: NewMemoryBroadcaster(),
+		epoch:       newEpoch(),
+		history:     newEventLog(replayBufferSize),
+		done:        make(chan struct{}),
 	}
 }
 
+// cursor names the last event the room delivered.
+func (r *AuctionRoom) cursor() EventCursor {
+	return EventCursor{Epoch: r.epoch, Seq: r.seq}
+}
+
+func (r *AuctionRoom) Done() <-chan struct{} {
+	return r.done
+}
+
 func NewClient(room *AuctionRoom, userId uuid.UUID, conn *websocket.Conn) *Client {
 	return &Client{
 		Room:   room,
@@ -150,7 +598,7 @@ func NewClient(room *AuctionRoom, userId uuid.UUID, conn *websocket.Conn) *Clien
 
 func (c *Client) ReadEventLoop() {
 	defer func() {
-		c.Room.Unregister <- c
+		c.unregister()
 		c.Conn.Close()
 	}()
 	c.Conn.SetReadLimit(maxMessageSize)
@@ -161,24 +609,41 @@ func (c *Client) ReadEventLoop() {
 	})
 	for {
 		var m Message
-		m.UserId = c.UserId
 		err := c.Conn.ReadJSON(&m)
 		if err != nil {
-			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
-				slog.Error("Unexpected close error", "error", err)
+			var syntaxErr *json.SyntaxError
+			var typeErr *json.UnmarshalTypeError
+			if !errors.As(err, &syntaxErr) && !errors.As(err, &typeErr) && !errors.Is(err, ErrInvalidAmount) {
+				if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
+					slog.Error("Unexpected close error", "error", err)
+				}
 				return
 			}
-			c.Room.Broadcast <- Message{
+			m = Message{
 				Kind:    InvalidJSON,
 				Message: "InvalidJSON",
-				UserId:  c.UserId,
 			}
-			continue
 		}
-		c.Room.Broadcast <- m
+		m.UserId, m.sender = c.UserId, c
+		select {
+		case c.Room.Broadcast <- m:
+		case <-c.Room.Done():
+			return
+		}
 	}
 }
 
+func (c *Client) unregister() {
+	select {
+	case c.Room.Unregister <- c:
+	case <-c.Room.Done():
+	}
+}
+
+func closesRoom(kind MessageKind) bool {
+	return kind == AuctionFinished || kind == SoldViaBuyNow || kind == PriceAccepted || kind == AuctionCancelled
+}
+
 func (c *Client) WriteEventLoop() {
 	ticker := time.NewTicker(pingPeriod)
 	defer func() {
@@ -195,14 +660,14 @@ func (c *Client) WriteEventLoop() {
 				})
 				return
 			}
-			if message.Kind == AuctionFinished {
-				close(c.Send)
-				return
-			}
 			c.Conn.SetWriteDeadline(time.Now().Add(writeWait))
 			err := c.Conn.WriteJSON(message)
 			if err != nil {
-				c.Room.Unregister <- c
+				c.unregister()
+				return
+			}
+			if closesRoom(message.Kind) {
+				c.Conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, message.Message))
 				return
 			}
 		case <-ticker.C:
@@ -0,0 +1,345 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/FelipePn10/Gobid/internal/store/pgstore"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeBroadcaster struct {
+	mu        sync.Mutex
+	published []Message
+	events    chan Message
+}
+
+func newFakeBroadcaster() *fakeBroadcaster {
+	return &fakeBroadcaster{events: make(chan Message, 16)}
+}
+
+func (b *fakeBroadcaster) Publish(ctx context.Context, auctionId uuid.UUID, m Message) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.published = append(b.published, m)
+	return nil
+}
+
+func (b *fakeBroadcaster) Subscribe(auctionId uuid.UUID) (<-chan Message, func()) {
+	return b.events, func() {}
+}
+
+// fakeStore answers the room's read queries from memory, scanning struct
+// fields in order just like the generated code does with SELECT *.
+type fakeStore struct {
+	product  *pgstore.Product
+	highest  *pgstore.Bid
+	bidCount int64
+}
+
+type fakeRow struct {
+	value any
+	err   error
+}
+
+func (r fakeRow) Scan(dest ...any) error {
+	if r.err != nil {
+		return r.err
+	}
+	v := reflect.ValueOf(r.value)
+	if v.Kind() != reflect.Struct {
+		reflect.ValueOf(dest[0]).Elem().Set(v)
+		return nil
+	}
+	for i := range dest {
+		reflect.ValueOf(dest[i]).Elem().Set(v.Field(i))
+	}
+	return nil
+}
+
+func (s fakeStore) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	switch {
+	case strings.Contains(sql, "name: GetProductById ") && s.product != nil:
+		return fakeRow{value: *s.product}
+	case strings.Contains(sql, "name: GetHighestBidByProductId ") && s.highest != nil:
+		return fakeRow{value: *s.highest}
+	case strings.Contains(sql, "name: CountBidsByProductId "):
+		return fakeRow{value: s.bidCount}
+	}
+	return fakeRow{err: pgx.ErrNoRows}
+}
+
+func (s fakeStore) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	panic("unexpected Exec in room test: " + sql)
+}
+
+func (s fakeStore) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	panic("unexpected Query in room test: " + sql)
+}
+
+func startTestRoom(t *testing.T, b Broadcaster) (*AuctionRoom, *Client) {
+	t.Helper()
+	return startTestRoomWithStore(t, b, fakeStore{}, uuid.New())
+}
+
+func startTestRoomWithStore(t *testing.T, b Broadcaster, store fakeStore, id uuid.UUID) (*AuctionRoom, *Client) {
+	t.Helper()
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(time.Hour))
+	t.Cleanup(cancel)
+	room := NewAuctionRoom(ctx, id, BidsService{queries: pgstore.New(store)})
+	room.Broadcaster = b
+	room.Currency = DefaultCurrency
+	go room.Run()
+
+	client := NewClient(room, uuid.New(), nil)
+	room.Register <- client
+	return room, client
+}
+
+func receive(t *testing.T, c *Client) Message {
+	t.Helper()
+	select {
+	case m := <-c.Send:
+		return m
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for a message")
+		return Message{}
+	}
+}
+
+func TestRoomDeliversBroadcastEvents(t *testing.T) {
+	b := newFakeBroadcaster()
+	_, client := startTestRoom(t, b)
+
+	b.events <- Message{Kind: NewBidPlaced, Amount: NewMoney(10_00, DefaultCurrency), UserId: uuid.New()}
+	if m := receive(t, client); m.Kind != NewBidPlaced || m.Amount.Amount != 10_00 {
+		t.Fatalf("unexpected message %+v", m)
+	}
+
+	b.events <- Message{Kind: NewBidPlaced, Amount: NewMoney(11_00, DefaultCurrency), UserId: client.UserId}
+	b.events <- Message{Kind: NewBidPlaced, Amount: NewMoney(12_00, DefaultCurrency), UserId: client.UserId, Automatic: true}
+	if m := receive(t, client); m.Amount.Amount != 12_00 {
+		t.Fatalf("bidder should only see the automatic bid, got %+v", m)
+	}
+}
+
+func TestRoomNamesBiddersByPseudonym(t *testing.T) {
+	b := newFakeBroadcaster()
+	room, client := startTestRoom(t, b)
+	bidder := uuid.New()
+
+	b.events <- Message{Kind: NewBidPlaced, Amount: NewMoney(10_00, DefaultCurrency), UserId: bidder}
+	m := receive(t, client)
+	if m.Bidder != BidderPseudonym(room.Id, bidder) || m.Mine {
+		t.Fatalf("expected another bidder's pseudonym, got %+v", m)
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), bidder.String()) {
+		t.Errorf("the bidder's id reached the client: %s", data)
+	}
+
+	b.events <- Message{Kind: AuctionFinished, Amount: NewMoney(10_00, DefaultCurrency), UserId: client.UserId}
+	if m := receive(t, client); !m.Mine || m.Bidder != BidderPseudonym(room.Id, client.UserId) {
+		t.Fatalf("expected the winner to recognise themselves, got %+v", m)
+	}
+}
+
+func TestRoomKeepsEveryConnectionOfAUser(t *testing.T) {
+	b := newFakeBroadcaster()
+	room, first := startTestRoom(t, b)
+	second := NewClient(room, first.UserId, nil)
+	room.Register <- second
+
+	b.events <- Message{Kind: NewBidPlaced, Amount: NewMoney(10_00, DefaultCurrency), UserId: uuid.New()}
+	for _, c := range []*Client{first, second} {
+		if m := receive(t, c); m.Kind != NewBidPlaced {
+			t.Fatalf("expected the bid on every connection, got %+v", m)
+		}
+	}
+
+	room.Broadcast <- Message{Kind: InvalidJSON, Message: "InvalidJSON", UserId: second.UserId, sender: second}
+	if m := receive(t, second); m.Kind != InvalidJSON {
+		t.Fatalf("expected the reply on the sending connection, got %+v", m)
+	}
+	select {
+	case m := <-first.Send:
+		t.Fatalf("the reply reached another connection: %+v", m)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestRoomAppliesPublishedExtension(t *testing.T) {
+	b := newFakeBroadcaster()
+	room, client := startTestRoom(t, b)
+
+	auctionEnd := time.Now().Add(2 * time.Hour).Truncate(time.Second)
+	b.events <- Message{Kind: AuctionExtended, AuctionEnd: &auctionEnd}
+	m := receive(t, client)
+	if m.Kind != AuctionExtended || !m.AuctionEnd.Equal(auctionEnd) {
+		t.Fatalf("unexpected message %+v", m)
+	}
+
+	// An echo of the same extension must not be announced twice.
+	b.events <- Message{Kind: AuctionExtended, AuctionEnd: &auctionEnd}
+	b.events <- Message{Kind: SoldViaBuyNow, Amount: NewMoney(50_00, DefaultCurrency)}
+	if m := receive(t, client); m.Kind != SoldViaBuyNow {
+		t.Fatalf("expected the sale, got %+v", m)
+	}
+	select {
+	case <-room.Done():
+	case <-time.After(time.Second):
+		t.Fatal("room did not close after the sale")
+	}
+	if len(b.published) != 0 {
+		t.Errorf("relayed events must not be published again, got %d", len(b.published))
+	}
+}
+
+func TestRoomAppliesRescheduledEnd(t *testing.T) {
+	b := newFakeBroadcaster()
+	_, client := startTestRoom(t, b)
+
+	// Unlike an extension, a new end is applied even when it is earlier.
+	for _, auctionEnd := range []time.Time{
+		time.Now().Add(3 * time.Hour).Truncate(time.Second),
+		time.Now().Add(2 * time.Hour).Truncate(time.Second),
+	} {
+		b.events <- Message{Kind: AuctionRescheduled, AuctionEnd: &auctionEnd}
+		if m := receive(t, client); m.Kind != AuctionRescheduled || !m.AuctionEnd.Equal(auctionEnd) {
+			t.Fatalf("unexpected message %+v", m)
+		}
+	}
+}
+
+func TestRoomClosesWhenCancelled(t *testing.T) {
+	b := newFakeBroadcaster()
+	room, client := startTestRoom(t, b)
+
+	b.events <- Message{Kind: AuctionCancelled, Message: "the auction was cancelled by the seller"}
+	if m := receive(t, client); m.Kind != AuctionCancelled {
+		t.Fatalf("expected the cancellation, got %+v", m)
+	}
+	select {
+	case <-room.Done():
+	case <-time.After(time.Second):
+		t.Fatal("room did not close after the cancellation")
+	}
+}
+
+func TestRoomReplaysMissedEvents(t *testing.T) {
+	b := newFakeBroadcaster()
+	room, client := startTestRoom(t, b)
+
+	for i := int64(1); i <= 3; i++ {
+		b.events <- Message{Kind: NewBidPlaced, Amount: NewMoney(i*10_00, DefaultCurrency), UserId: uuid.New()}
+		if m := receive(t, client); m.Seq != uint64(i) {
+			t.Fatalf("expected seq %d, got %d", i, m.Seq)
+		}
+	}
+
+	reconnected := NewClient(room, uuid.New(), nil)
+	reconnected.LastEvent = &EventCursor{Epoch: room.epoch, Seq: 1}
+	room.Register <- reconnected
+	for _, want := range []uint64{2, 3} {
+		if m := receive(t, reconnected); m.Seq != want {
+			t.Fatalf("expected replayed seq %d, got %+v", want, m)
+		}
+	}
+
+	// The same sequence number from another run of the room says nothing
+	// about what this run delivered.
+	elsewhere := NewClient(room, uuid.New(), nil)
+	elsewhere.LastEvent = &EventCursor{Epoch: "another-run", Seq: 1}
+	room.Register <- elsewhere
+	if m := receive(t, elsewhere); m.Kind != ReplayIncomplete {
+		t.Fatalf("expected a warning about the incomplete replay, got %+v", m)
+	}
+	for _, want := range []uint64{1, 2, 3} {
+		if m := receive(t, elsewhere); m.Seq != want {
+			t.Fatalf("expected buffered seq %d, got %+v", want, m)
+		}
+	}
+
+	// A bare sequence number resumes within the current run.
+	bare := NewClient(room, uuid.New(), nil)
+	bare.LastEvent = &EventCursor{Seq: 2}
+	room.Register <- bare
+	if m := receive(t, bare); m.Seq != 3 {
+		t.Fatalf("expected replayed seq 3, got %+v", m)
+	}
+	ahead := NewClient(room, uuid.New(), nil)
+	ahead.LastEvent = &EventCursor{Seq: 7}
+	room.Register <- ahead
+	if m := receive(t, ahead); m.Kind != ReplayIncomplete {
+		t.Fatalf("expected a warning about a sequence number from another run, got %+v", m)
+	}
+}
+
+func TestRoomSendsSnapshotOnRegister(t *testing.T) {
+	id, bidder := uuid.New(), uuid.New()
+	store := fakeStore{
+		product: &pgstore.Product{
+			ID:           id,
+			ProductName:  "snapshot test product",
+			Baseprice:    10_00,
+			ReservePrice: 50_00,
+			AuctionType:  AuctionEnglish,
+			AuctionStart: time.Now().Add(-time.Minute),
+			AuctionEnd:   time.Now().Add(time.Hour),
+			Currency:     DefaultCurrency,
+		},
+		highest:  &pgstore.Bid{ProductID: id, BidderID: bidder, BidAmount: 30_00},
+		bidCount: 4,
+	}
+	_, client := startTestRoomWithStore(t, newFakeBroadcaster(), store, id)
+
+	m := receive(t, client)
+	if m.Kind != RoomSnapshot || m.Snapshot == nil {
+		t.Fatalf("expected a snapshot, got %+v", m)
+	}
+	snapshot := m.Snapshot
+	if snapshot.HighBid == nil || snapshot.HighBid.Amount != 30_00 || snapshot.BidCount == nil || *snapshot.BidCount != 4 || snapshot.Watchers != 1 {
+		t.Errorf("unexpected snapshot %+v", snapshot)
+	}
+	if snapshot.HighBidder != BidderPseudonym(id, bidder) {
+		t.Errorf("expected the bidder pseudonym, got %q", snapshot.HighBidder)
+	}
+	if snapshot.ReserveMet == nil || *snapshot.ReserveMet {
+		t.Errorf("the reserve should be reported as not met")
+	}
+}
+
+func TestSealedSnapshotHidesTheBids(t *testing.T) {
+	id := uuid.New()
+	store := fakeStore{
+		product: &pgstore.Product{
+			ID:           id,
+			ProductName:  "sealed snapshot test product",
+			Baseprice:    10_00,
+			AuctionType:  AuctionSealed,
+			AuctionStart: time.Now().Add(-time.Minute),
+			AuctionEnd:   time.Now().Add(time.Hour),
+			Currency:     DefaultCurrency,
+		},
+		highest:  &pgstore.Bid{ProductID: id, BidderID: uuid.New(), BidAmount: 30_00},
+		bidCount: 4,
+	}
+	_, client := startTestRoomWithStore(t, newFakeBroadcaster(), store, id)
+
+	m := receive(t, client)
+	if m.Kind != RoomSnapshot || m.Snapshot == nil {
+		t.Fatalf("expected a snapshot, got %+v", m)
+	}
+	if snapshot := m.Snapshot; snapshot.BidCount != nil || snapshot.HighBid != nil || snapshot.HighBidder != "" {
+		t.Errorf("the sealed snapshot reveals the bids: %+v", snapshot)
+	}
+}
@@ -0,0 +1,257 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"github.com/FelipePn10/Gobid/internal/store/pgstore"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"slices"
+	"strconv"
+	"time"
+)
+
+const (
+	DefaultBidPageSize = 20
+	MaxBidPageSize     = 100
+	maxTimelineBids    = 500
+)
+
+const (
+	TimelineCreated   = "created"
+	TimelineScheduled = "scheduled"
+	TimelineStarted   = "started"
+	TimelineBid       = "bid"
+	TimelineExtended  = "extended"
+	TimelineEnded     = "ended"
+	TimelineSold      = "sold"
+	TimelineCancelled = "cancelled"
+	TimelinePaid      = "paid"
+)
+
+// timelineKinds maps the statuses a product moves to onto timeline entries.
+// Sales come from the auction result, which says more.
+var timelineKinds = map[string]string{
+	ProductScheduled: TimelineScheduled,
+	ProductLive:      TimelineStarted,
+	ProductEnded:     TimelineEnded,
+	ProductCancelled: TimelineCancelled,
+	ProductPaid:      TimelinePaid,
+}
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// BidView is a bid as shown to one viewer. Bidders appear under a
+// pseudonym; only the seller sees who they are.
+type BidView struct {
+	ID         uuid.UUID  `json:"id"`
+	Amount     Money      `json:"amount"`
+	Bidder     string     `json:"bidder"`
+	BidderID   *uuid.UUID `json:"bidder_id,omitempty"`
+	BidderName string     `json:"bidder_name,omitempty"`
+	IsYours    bool       `json:"is_yours"`
+	PlacedAt   time.Time  `json:"placed_at"`
+}
+
+type BidPage struct {
+	Bids       []BidView `json:"bids"`
+	NextCursor string    `json:"next_cursor,omitempty"`
+}
+
+// Timeline is the history of an auction. It includes at most
+// maxTimelineBids bids, the newest ones; Truncated reports that older bids
+// were left out and must be paged through with ListBids.
+type Timeline struct {
+	Entries   []TimelineEntry `json:"timeline"`
+	Truncated bool            `json:"truncated"`
+}
+
+type TimelineEntry struct {
+	Kind       string     `json:"kind"`
+	At         time.Time  `json:"at"`
+	Bid        *BidView   `json:"bid,omitempty"`
+	AuctionEnd *time.Time `json:"auction_end,omitempty"`
+	Amount     *Money     `json:"amount,omitempty"`
+	Winner     string     `json:"winner,omitempty"`
+	SoldVia    string     `json:"sold_via,omitempty"`
+}
+
+// ListBids returns the bids of an auction newest first, a page at a time.
+// While a sealed auction is open every viewer only sees their own bids.
+func (bs *BidsService) ListBids(ctx context.Context, productId, viewerId uuid.UUID, cursor string, pageSize int32) (BidPage, error) {
+	product, err := bs.visibleProduct(ctx, productId, viewerId)
+	if err != nil {
+		return BidPage{}, err
+	}
+	params := pgstore.ListBidsByProductIdParams{
+		ProductID: productId,
+		BidderID:  bidderFilter(product, viewerId),
+		PageSize:  pageSize + 1,
+	}
+	if cursor != "" {
+		seq, err := decodeBidCursor(cursor)
+		if err != nil {
+			return BidPage{}, err
+		}
+		params.BeforeSeq = pgtype.Int8{Int64: seq, Valid: true}
+	}
+	rows, err := bs.queries.ListBidsByProductId(ctx, params)
+	if err != nil {
+		return BidPage{}, err
+	}
+
+	page := BidPage{Bids: make([]BidView, 0, len(rows))}
+	if len(rows) > int(pageSize) {
+		rows = rows[:pageSize]
+		last := rows[len(rows)-1]
+		page.NextCursor = encodeBidCursor(last.Seq)
+	}
+	for _, row := range rows {
+		page.Bids = append(page.Bids, bidView(product, viewerId, row))
+	}
+	return page, nil
+}
+
+// Timeline merges the bids, soft close extensions and status changes of an
+// auction in the order they happened.
+func (bs *BidsService) Timeline(ctx context.Context, productId, viewerId uuid.UUID) (Timeline, error) {
+	product, err := bs.visibleProduct(ctx, productId, viewerId)
+	if err != nil {
+		return Timeline{}, err
+	}
+	timeline := []TimelineEntry{{Kind: TimelineCreated, At: product.CreatedAt}}
+	transitions, err := bs.queries.ListProductStatusTransitions(ctx, productId)
+	if err != nil {
+		return Timeline{}, err
+	}
+	for _, transition := range transitions {
+		kind, ok := timelineKinds[transition.ToStatus]
+		if !transition.FromStatus.Valid || !ok {
+			continue
+		}
+		timeline = append(timeline, TimelineEntry{Kind: kind, At: transition.CreatedAt})
+	}
+	// Starting and ending are only recorded once something touches the
+	// auction, so fill in what the clock says has already happened.
+	now, status := time.Now(), product.Status
+	if status == ProductScheduled && !product.AuctionStart.After(now) {
+		timeline = append(timeline, TimelineEntry{Kind: TimelineStarted, At: product.AuctionStart})
+		status = ProductLive
+	}
+	if status == ProductLive && !product.AuctionEnd.After(now) {
+		timeline = append(timeline, TimelineEntry{Kind: TimelineEnded, At: product.AuctionEnd})
+	}
+
+	bids, err := bs.queries.ListBidsByProductId(ctx, pgstore.ListBidsByProductIdParams{
+		ProductID: productId,
+		BidderID:  bidderFilter(product, viewerId),
+		PageSize:  maxTimelineBids + 1,
+	})
+	if err != nil {
+		return Timeline{}, err
+	}
+	truncated := len(bids) > maxTimelineBids
+	if truncated {
+		bids = bids[:maxTimelineBids]
+	}
+	// Bids come newest first. Adding them oldest first keeps bids placed
+	// together, which share a timestamp, in order through the stable sort.
+	for _, row := range slices.Backward(bids) {
+		view := bidView(product, viewerId, row)
+		timeline = append(timeline, TimelineEntry{Kind: TimelineBid, At: row.CreatedAt, Bid: &view})
+	}
+
+	extensions, err := bs.queries.ListAuctionExtensionsByProductId(ctx, productId)
+	if err != nil {
+		return Timeline{}, err
+	}
+	for _, extension := range extensions {
+		timeline = append(timeline, TimelineEntry{Kind: TimelineExtended, At: extension.CreatedAt, AuctionEnd: &extension.NewEnd})
+	}
+
+	result, err := bs.queries.GetAuctionResultByProductId(ctx, productId)
+	switch {
+	case err == nil:
+		timeline = append(timeline, TimelineEntry{
+			Kind:    TimelineSold,
+			At:      result.ClosedAt,
+			Amount:  NewMoney(result.HammerPrice, product.Currency),
+			Winner:  BidderPseudonym(productId, result.WinnerID),
+			SoldVia: result.SoldVia,
+		})
+	case !errors.Is(err, pgx.ErrNoRows):
+		return Timeline{}, err
+	}
+
+	slices.SortStableFunc(timeline, func(a, b TimelineEntry) int {
+		return a.At.Compare(b.At)
+	})
+	return Timeline{Entries: timeline, Truncated: truncated}, nil
+}
+
+func (bs *BidsService) getProduct(ctx context.Context, productId uuid.UUID) (pgstore.Product, error) {
+	product, err := bs.queries.GetProductById(ctx, productId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return pgstore.Product{}, ErrProductNotFound
+		}
+		return pgstore.Product{}, err
+	}
+	return product, nil
+}
+
+// visibleProduct returns the product whose bids viewerId asked for. Drafts
+// are private to their seller, so anyone else is told they do not exist.
+func (bs *BidsService) visibleProduct(ctx context.Context, productId, viewerId uuid.UUID) (pgstore.Product, error) {
+	product, err := bs.getProduct(ctx, productId)
+	if err != nil {
+		return pgstore.Product{}, err
+	}
+	if product.Status == ProductDraft && product.SellerID != viewerId {
+		return pgstore.Product{}, ErrProductNotFound
+	}
+	return product, nil
+}
+
+func bidderFilter(product pgstore.Product, viewerId uuid.UUID) pgtype.UUID {
+	sealed := product.AuctionType == AuctionSealed || product.AuctionType == AuctionVickrey
+	status := EffectiveStatus(product, time.Now())
+	if sealed && (status == ProductScheduled || status == ProductLive) {
+		return pgtype.UUID{Bytes: viewerId, Valid: true}
+	}
+	return pgtype.UUID{}
+}
+
+func bidView(product pgstore.Product, viewerId uuid.UUID, row pgstore.ListBidsByProductIdRow) BidView {
+	view := BidView{
+		ID:       row.ID,
+		Amount:   Money{Amount: row.BidAmount, Currency: product.Currency},
+		Bidder:   BidderPseudonym(product.ID, row.BidderID),
+		IsYours:  row.BidderID == viewerId,
+		PlacedAt: row.CreatedAt,
+	}
+	if viewerId == product.SellerID {
+		bidderId := row.BidderID
+		view.BidderID = &bidderId
+		view.BidderName = row.BidderName
+	}
+	return view
+}
+
+func encodeBidCursor(seq int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(seq, 10)))
+}
+
+func decodeBidCursor(cursor string) (int64, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, ErrInvalidCursor
+	}
+	seq, err := strconv.ParseInt(string(raw), 10, 64)
+	if err != nil {
+		return 0, ErrInvalidCursor
+	}
+	return seq, nil
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"github.com/FelipePn10/Gobid/internal/store/pgstore"
+	"testing"
+	"time"
+)
+
+func TestBidCursorRoundTrip(t *testing.T) {
+	for _, seq := range []int64{1, 42, 9223372036854775807} {
+		got, err := decodeBidCursor(encodeBidCursor(seq))
+		if err != nil || got != seq {
+			t.Fatalf("decodeBidCursor(encodeBidCursor(%d)) = %d, %v", seq, got, err)
+		}
+	}
+}
+
+func TestDecodeBidCursorRejectsGarbage(t *testing.T) {
+	for _, cursor := range []string{"%%%", "bm90LWEtY3Vyc29y", "MTIzOm5vdC1hLXV1aWQ"} {
+		if _, err := decodeBidCursor(cursor); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("decodeBidCursor(%q) = %v, want ErrInvalidCursor", cursor, err)
+		}
+	}
+}
+
+func TestTimelineKeepsBidsPlacedTogetherInOrder(t *testing.T) {
+	pool := testPool(t)
+	q := pgstore.New(pool)
+	bs := NewBidsService(pool)
+	ctx := context.Background()
+
+	sellerId := createTestUser(t, q)
+	productId := createTestAuction(t, q, sellerId)
+	proxy, bidder := createTestUser(t, q), createTestUser(t, q)
+	if _, err := bs.PlaceMaxBid(ctx, productId, proxy, Money{Amount: 200_00}); err != nil {
+		t.Fatal(err)
+	}
+	// The automatic counter-bid is placed in the same transaction as this
+	// bid, so both share created_at.
+	if _, err := bs.Placebid(ctx, productId, bidder, Money{Amount: 150_00}); err != nil {
+		t.Fatal(err)
+	}
+
+	timeline, err := bs.Timeline(ctx, productId, sellerId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var amounts []int64
+	for _, entry := range timeline.Entries {
+		if entry.Kind == TimelineBid {
+			amounts = append(amounts, entry.Bid.Amount.Amount)
+		}
+	}
+	if len(amounts) < 2 || amounts[len(amounts)-2] != 150_00 || amounts[len(amounts)-1] <= 150_00 {
+		t.Fatalf("bid amounts in the timeline = %v, want the counter-bid after 150_00", amounts)
+	}
+	if timeline.Truncated {
+		t.Error("a short timeline should not be truncated")
+	}
+}
+
+func TestDraftBidsAreHiddenFromOthers(t *testing.T) {
+	pool := testPool(t)
+	q := pgstore.New(pool)
+	ps := NewProductsService(pool)
+	bs := NewBidsService(pool)
+	ctx := context.Background()
+
+	sellerId := createTestUser(t, q)
+	productId, err := ps.CreateProduct(ctx, sellerId, "draft product", "a product that has not been published yet",
+		100_00, time.Now().Add(3*time.Hour), AuctionSettings{
+			Type:      AuctionEnglish,
+			Currency:  DefaultCurrency,
+			Increment: IncrementPolicy{Type: IncrementFixed, Value: 1_00},
+			Draft:     true,
+		}, ProductClassification{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stranger := createTestUser(t, q)
+	if _, err := bs.ListBids(ctx, productId, stranger, "", DefaultBidPageSize); !errors.Is(err, ErrProductNotFound) {
+		t.Errorf("listing a draft's bids as a stranger = %v, want ErrProductNotFound", err)
+	}
+	if _, err := bs.Timeline(ctx, productId, stranger); !errors.Is(err, ErrProductNotFound) {
+		t.Errorf("loading a draft's timeline as a stranger = %v, want ErrProductNotFound", err)
+	}
+	if _, err := bs.Timeline(ctx, productId, sellerId); err != nil {
+		t.Errorf("loading a draft's timeline as its seller = %v, want nil", err)
+	}
+}
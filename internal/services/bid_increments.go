@@ -0,0 +1,75 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"github.com/FelipePn10/Gobid/internal/store/pgstore"
+	"math"
+)
+
+const (
+	IncrementFixed      = "fixed"
+	IncrementPercentage = "percentage"
+	IncrementTiered     = "tiered"
+)
+
+// Value holds minor units for fixed increments and basis points for
+// percentage increments.
+type IncrementPolicy struct {
+	Type  string
+	Value int64
+}
+
+// DefaultIncrementPolicy raises bids by one whole unit of currency.
+func DefaultIncrementPolicy(currency string) IncrementPolicy {
+	return IncrementPolicy{Type: IncrementFixed, Value: int64(math.Pow10(decimalsOf(currency)))}
+}
+
+var ErrBidBelowIncrement = errors.New("the bid does not meet the minimum increment")
+
+type BidBelowIncrementError struct {
+	NextMinimum Money
+}
+
+func (e *BidBelowIncrementError) Error() string {
+	return fmt.Sprintf("%s, the next acceptable bid is %s", ErrBidBelowIncrement, e.NextMinimum)
+}
+
+func (e *BidBelowIncrementError) Is(target error) bool {
+	return target == ErrBidBelowIncrement
+}
+
+var tieredIncrements = []struct {
+	upTo      int64
+	increment int64
+}{
+	{1_00, 5},
+	{5_00, 25},
+	{25_00, 50},
+	{100_00, 1_00},
+	{250_00, 2_50},
+	{500_00, 5_00},
+	{1000_00, 10_00},
+	{2500_00, 25_00},
+	{5000_00, 50_00},
+	{math.MaxInt64, 100_00},
+}
+
+func minimumIncrement(product pgstore.Product, price int64) int64 {
+	switch product.IncrementType {
+	case IncrementPercentage:
+		return (price*product.IncrementValue + 9999) / 10000
+	case IncrementTiered:
+		for _, tier := range tieredIncrements {
+			if price < tier.upTo {
+				return tier.increment
+			}
+		}
+	}
+	return product.IncrementValue
+}
+
+func nextMinimumBid(product pgstore.Product, highest pgstore.Bid) int64 {
+	price := max(product.Baseprice, highest.BidAmount)
+	return price + minimumIncrement(product, price)
+}
@@ -0,0 +1,50 @@
+package services
+
+import (
+	"github.com/FelipePn10/Gobid/internal/store/pgstore"
+	"math"
+	"testing"
+)
+
+func TestMinimumIncrement(t *testing.T) {
+	tests := []struct {
+		incrementType  string
+		incrementValue int64
+		price          int64
+		want           int64
+	}{
+		{IncrementFixed, 1_00, 100_00, 1_00},
+		{IncrementFixed, 2_50, 5, 2_50},
+		// Percentages are basis points, rounded up to the next minor unit.
+		{IncrementPercentage, 500, 100_00, 5_00},
+		{IncrementPercentage, 500, 100_01, 5_01},
+		{IncrementPercentage, 500, 100_20, 5_01},
+		{IncrementPercentage, 250, 1, 1},
+		{IncrementPercentage, 1, 99_99, 1},
+		{IncrementPercentage, 1, 100_01, 2},
+		// Each tier applies up to, but not including, its upper bound.
+		{IncrementTiered, 0, 99, 5},
+		{IncrementTiered, 0, 1_00, 25},
+		{IncrementTiered, 0, 4_99, 25},
+		{IncrementTiered, 0, 5_00, 50},
+		{IncrementTiered, 0, 99_99, 1_00},
+		{IncrementTiered, 0, 100_00, 2_50},
+		{IncrementTiered, 0, 4999_99, 50_00},
+		{IncrementTiered, 0, 5000_00, 100_00},
+		{IncrementTiered, 0, math.MaxInt64 - 1, 100_00},
+	}
+	for _, tt := range tests {
+		product := pgstore.Product{IncrementType: tt.incrementType, IncrementValue: tt.incrementValue}
+		if got := minimumIncrement(product, tt.price); got != tt.want {
+			t.Errorf("minimumIncrement(%s %d, %d) = %d, want %d", tt.incrementType, tt.incrementValue, tt.price, got, tt.want)
+		}
+	}
+}
+
+func TestDefaultIncrementPolicyIsOneUnit(t *testing.T) {
+	for currency, want := range map[string]int64{"USD": 1_00, "JPY": 1, "KWD": 1_000} {
+		if got := DefaultIncrementPolicy(currency); got.Type != IncrementFixed || got.Value != want {
+			t.Errorf("DefaultIncrementPolicy(%s) = %+v, want fixed %d", currency, got, want)
+		}
+	}
+}
@@ -3,10 +3,13 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
 	"github.com/FelipePn10/Gobid/internal/store/pgstore"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"log/slog"
+	"time"
 )
 
 type BidsService struct {
@@ -14,7 +17,41 @@ type BidsService struct {
 	queries *pgstore.Queries
 }
 
-var ErrBidIsTooLow = errors.New("the bid value is too low")
+var (
+	ErrBidIsTooLow       = errors.New("the bid value is too low")
+	ErrAuctionHasNoBids  = errors.New("the auction has no bids")
+	ErrReserveNotMet     = errors.New("the reserve price was not met")
+	ErrBuyNowUnavailable = errors.New("the product can no longer be bought at the buy now price")
+	ErrAuctionStillOpen  = errors.New("the auction is still open")
+)
+
+// AuctionStillOpenError is returned when settlement is attempted before the
+// stored deadline, which another instance may have extended.
+type AuctionStillOpenError struct {
+	AuctionEnd time.Time
+}
+
+func (e *AuctionStillOpenError) Error() string {
+	return fmt.Sprintf("%s until %s", ErrAuctionStillOpen, e.AuctionEnd.Format(time.RFC3339))
+}
+
+func (e *AuctionStillOpenError) Is(target error) bool {
+	return target == ErrAuctionStillOpen
+}
+
+const (
+	SaleByAuction = "auction"
+	SaleByBuyNow  = "buy_now"
+)
+
+type PlacedBid struct {
+	Bid        pgstore.Bid
+	ProxyBids  []pgstore.Bid
+	AuctionEnd time.Time
+	Extended   bool
+	ReserveMet *bool
+	Sealed     bool
+}
 
 func NewBidsService(pool *pgxpool.Pool) BidsService {
 	return BidsService{
@@ -23,29 +60,406 @@ func NewBidsService(pool *pgxpool.Pool) BidsService {
 	}
 }
 
-func (bs *BidsService) Placebid(ctx context.Context, product_id, bidder_id uuid.UUID, amount float64) (pgstore.Bid, error) {
-	product, err := bs.queries.GetProductById(ctx, product_id)
+func (bs *BidsService) Placebid(ctx context.Context, product_id, bidder_id uuid.UUID, bidAmount Money) (PlacedBid, error) {
+	return bs.withLockedAuction(ctx, product_id, func(qtx *pgstore.Queries, product pgstore.Product) (PlacedBid, error) {
+		bidAmount, err := bidAmount.In(product.Currency)
+		if err != nil {
+			return PlacedBid{}, err
+		}
+		amount := bidAmount.Amount
+		switch product.AuctionType {
+		case AuctionSealed, AuctionVickrey:
+			return placeSealedBid(ctx, qtx, product, bidder_id, amount)
+		case AuctionEnglish:
+		default:
+			return PlacedBid{}, ErrWrongAuctionType
+		}
+		highestBid, err := qtx.GetHighestBidByProductId(ctx, product_id)
+		if err != nil {
+			if !errors.Is(err, pgx.ErrNoRows) {
+				return PlacedBid{}, err
+			}
+		}
+		if product.Baseprice >= amount || highestBid.BidAmount >= amount {
+			return PlacedBid{}, ErrBidIsTooLow
+		}
+		if next := nextMinimumBid(product, highestBid); amount < next {
+			return PlacedBid{}, &BidBelowIncrementError{NextMinimum: Money{Amount: next, Currency: product.Currency}}
+		}
+		bid, err := qtx.CreateBid(ctx, pgstore.CreateBidParams{
+			ProductID: product_id,
+			BidderID:  bidder_id,
+			BidAmount: amount,
+		})
+		if err != nil {
+			return PlacedBid{}, err
+		}
+		proxyBids, err := resolveProxyBids(ctx, qtx, product, bid)
+		if err != nil {
+			return PlacedBid{}, err
+		}
+		return applySoftClose(ctx, qtx, product, PlacedBid{Bid: bid, ProxyBids: proxyBids})
+	})
+}
+
+func (bs *BidsService) PlaceMaxBid(ctx context.Context, product_id, bidder_id uuid.UUID, maxBid Money) (PlacedBid, error) {
+	return bs.withLockedAuction(ctx, product_id, func(qtx *pgstore.Queries, product pgstore.Product) (PlacedBid, error) {
+		maxBid, err := maxBid.In(product.Currency)
+		if err != nil {
+			return PlacedBid{}, err
+		}
+		maxAmount := maxBid.Amount
+		if product.AuctionType != AuctionEnglish {
+			return PlacedBid{}, ErrWrongAuctionType
+		}
+		highestBid, err := qtx.GetHighestBidByProductId(ctx, product_id)
+		if err != nil {
+			if !errors.Is(err, pgx.ErrNoRows) {
+				return PlacedBid{}, err
+			}
+		}
+		if product.Baseprice >= maxAmount || highestBid.BidAmount >= maxAmount {
+			return PlacedBid{}, ErrBidIsTooLow
+		}
+		if next := nextMinimumBid(product, highestBid); maxAmount < next {
+			return PlacedBid{}, &BidBelowIncrementError{NextMinimum: Money{Amount: next, Currency: product.Currency}}
+		}
+		_, err = qtx.UpsertMaxBid(ctx, pgstore.UpsertMaxBidParams{
+			ProductID: product_id,
+			BidderID:  bidder_id,
+			MaxAmount: maxAmount,
+		})
+		if err != nil {
+			return PlacedBid{}, err
+		}
+		proxyBids, err := resolveProxyBids(ctx, qtx, product, highestBid)
+		if err != nil {
+			return PlacedBid{}, err
+		}
+		return applySoftClose(ctx, qtx, product, PlacedBid{ProxyBids: proxyBids})
+	})
+}
+
+// withLockedAuction runs fn in a transaction holding the product row lock, so
+// concurrent bids on the same product are checked against each other's writes.
+func (bs *BidsService) withLockedAuction(
+	ctx context.Context,
+	productId uuid.UUID,
+	fn func(qtx *pgstore.Queries, product pgstore.Product) (PlacedBid, error),
+) (PlacedBid, error) {
+	tx, err := bs.pool.Begin(ctx)
+	if err != nil {
+		return PlacedBid{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := bs.queries.WithTx(tx)
+	product, err := qtx.GetProductByIdForUpdate(ctx, productId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return PlacedBid{}, ErrProductNotFound
+		}
+		return PlacedBid{}, err
+	}
+	if err := openAuction(ctx, qtx, &product, time.Now()); err != nil {
+		return PlacedBid{}, err
+	}
+	placed, err := fn(qtx, product)
+	if err != nil {
+		return PlacedBid{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return PlacedBid{}, err
+	}
+	return placed, nil
+}
+
+func checkAuctionIsOpen(product pgstore.Product, now time.Time) error {
+	switch EffectiveStatus(product, now) {
+	case ProductLive:
+		return nil
+	case ProductDraft, ProductScheduled:
+		return ErrAuctionNotStarted
+	default:
+		return ErrAuctionHasEnded
+	}
+}
+
+// openAuction checks that the locked product takes bids and records that it
+// went live if that has not happened yet.
+func openAuction(ctx context.Context, qtx *pgstore.Queries, product *pgstore.Product, now time.Time) error {
+	if err := checkAuctionIsOpen(*product, now); err != nil {
+		return err
+	}
+	return advanceProductStatus(ctx, qtx, product, now)
+}
+
+// resolveProxyBids places the bids proxyBids decides on.
+func resolveProxyBids(ctx context.Context, qtx *pgstore.Queries, product pgstore.Product, highest pgstore.Bid) ([]pgstore.Bid, error) {
+	maxBids, err := qtx.GetMaxBidsByProductId(ctx, product.ID)
+	if err != nil {
+		return nil, err
+	}
+	var bids []pgstore.Bid
+	for _, proxy := range proxyBids(product, maxBids, highest) {
+		b, err := qtx.CreateBid(ctx, pgstore.CreateBidParams{
+			ProductID: product.ID,
+			BidderID:  proxy.BidderID,
+			BidAmount: proxy.Amount,
+		})
+		if err != nil {
+			return nil, err
+		}
+		bids = append(bids, b)
+	}
+	return bids, nil
+}
+
+type proxyBid struct {
+	BidderID uuid.UUID
+	Amount   int64
+}
+
+// proxyBids bids on behalf of the two highest maximum bids, which must be
+// ordered as GetMaxBidsByProductId orders them, until the higher one leads by
+// the minimum increment or reaches its ceiling. When both maximums are equal
+// the one registered first wins at that amount.
+func proxyBids(product pgstore.Product, maxBids []pgstore.MaxBid, highest pgstore.Bid) []proxyBid {
+	if len(maxBids) == 0 {
+		return nil
+	}
+
+	var bids []proxyBid
+	leader, price := highest.BidderID, max(product.Baseprice, highest.BidAmount)
+	bid := func(bidderId uuid.UUID, amount int64) {
+		bids = append(bids, proxyBid{BidderID: bidderId, Amount: amount})
+		leader, price = bidderId, amount
+	}
+
+	top, level := maxBids[0], price
+	if len(maxBids) > 1 && maxBids[1].MaxAmount > price {
+		challenger := maxBids[1]
+		if challenger.MaxAmount == top.MaxAmount {
+			bid(top.BidderID, top.MaxAmount)
+			return bids
+		}
+		if challenger.BidderID != leader && challenger.MaxAmount >= price+minimumIncrement(product, price) {
+			bid(challenger.BidderID, challenger.MaxAmount)
+		}
+		level = challenger.MaxAmount
+	}
+	if top.BidderID != leader && top.MaxAmount > level {
+		bid(top.BidderID, min(top.MaxAmount, level+minimumIncrement(product, level)))
+	}
+	return bids
+}
+
+func reserveMet(product pgstore.Product, amount int64) *bool {
+	if product.ReservePrice <= 0 {
+		return nil
+	}
+	met := amount >= product.ReservePrice
+	return &met
+}
+
+func applySoftClose(ctx context.Context, qtx *pgstore.Queries, product pgstore.Product, placed PlacedBid) (PlacedBid, error) {
+	placed.AuctionEnd = product.AuctionEnd
+	last := placed.Bid
+	if len(placed.ProxyBids) > 0 {
+		last = placed.ProxyBids[len(placed.ProxyBids)-1]
+	}
+	if last.ID == uuid.Nil {
+		return placed, nil
+	}
+	placed.ReserveMet = reserveMet(product, last.BidAmount)
+	end, ok := softCloseExtension(product, last.CreatedAt)
+	if !ok {
+		return placed, nil
+	}
+	err := qtx.UpdateProductAuctionEnd(ctx, pgstore.UpdateProductAuctionEndParams{
+		ID:         product.ID,
+		AuctionEnd: end,
+	})
+	if err != nil {
+		return PlacedBid{}, err
+	}
+	err = qtx.CreateAuctionExtension(ctx, pgstore.CreateAuctionExtensionParams{
+		ProductID:   product.ID,
+		BidID:       last.ID,
+		PreviousEnd: product.AuctionEnd,
+		NewEnd:      end,
+	})
+	if err != nil {
+		return PlacedBid{}, err
+	}
+	placed.AuctionEnd = end
+	placed.Extended = true
+	return placed, nil
+}
+
+func softCloseExtension(product pgstore.Product, bidTime time.Time) (time.Time, bool) {
+	if product.SoftCloseWindowMinutes <= 0 || product.SoftCloseExtensionMinutes <= 0 {
+		return time.Time{}, false
+	}
+	window := time.Duration(product.SoftCloseWindowMinutes) * time.Minute
+	if product.AuctionEnd.Sub(bidTime) > window {
+		return time.Time{}, false
+	}
+	return product.AuctionEnd.Add(time.Duration(product.SoftCloseExtensionMinutes) * time.Minute), true
+}
+
+func (bs *BidsService) SettleAuction(ctx context.Context, productId uuid.UUID) (pgstore.AuctionResult, error) {
+	tx, err := bs.pool.Begin(ctx)
+	if err != nil {
+		return pgstore.AuctionResult{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := bs.queries.WithTx(tx)
+	product, err := qtx.GetProductByIdForUpdate(ctx, productId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return pgstore.AuctionResult{}, ErrProductNotFound
+		}
+		return pgstore.AuctionResult{}, err
+	}
+	switch product.Status {
+	case ProductSold, ProductPaid:
+		return qtx.GetAuctionResultByProductId(ctx, productId)
+	case ProductCancelled:
+		return pgstore.AuctionResult{}, ErrAuctionCancelled
+	case ProductDraft:
+		return pgstore.AuctionResult{}, &InvalidStatusTransitionError{From: product.Status, To: ProductSold}
+	}
+	now := time.Now()
+	if product.AuctionEnd.After(now) {
+		return pgstore.AuctionResult{}, &AuctionStillOpenError{AuctionEnd: product.AuctionEnd}
+	}
+	if err := advanceProductStatus(ctx, qtx, &product, now); err != nil {
+		return pgstore.AuctionResult{}, err
+	}
+	bids, err := qtx.GetBidsByProductId(ctx, productId)
+	if err != nil {
+		return pgstore.AuctionResult{}, err
+	}
+	if len(bids) == 0 {
+		return pgstore.AuctionResult{}, closeUnsold(ctx, tx, qtx, &product, ErrAuctionHasNoBids)
+	}
+	highestBid := bids[0]
+	if highestBid.BidAmount < product.ReservePrice {
+		return pgstore.AuctionResult{}, closeUnsold(ctx, tx, qtx, &product, ErrReserveNotMet)
+	}
+	result, err := qtx.CreateAuctionResult(ctx, pgstore.CreateAuctionResultParams{
+		ProductID:   productId,
+		WinnerID:    highestBid.BidderID,
+		BidID:       highestBid.ID,
+		HammerPrice: hammerPrice(product, bids),
+		SoldVia:     SaleByAuction,
+	})
+	if err != nil {
+		return pgstore.AuctionResult{}, err
+	}
+	if err := transitionProduct(ctx, qtx, &product, ProductSold, nil, product.AuctionEnd); err != nil {
+		return pgstore.AuctionResult{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return pgstore.AuctionResult{}, err
+	}
+	return result, nil
+}
+
+// SettleOverdueAuctions settles the auctions that ended before endedBefore
+// without being settled, such as those that closed while no instance was
+// running or whose room failed to settle them.
+func (bs *BidsService) SettleOverdueAuctions(ctx context.Context, endedBefore time.Time) error {
+	products, err := bs.queries.ListOverdueAuctions(ctx, endedBefore)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, product := range products {
+		result, err := bs.SettleAuction(ctx, product.ID)
+		var stillOpen *AuctionStillOpenError
+		switch {
+		case err == nil:
+			slog.Info("Overdue auction settled", "auctionID", product.ID, "winner", result.WinnerID, "hammer_price", result.HammerPrice)
+		case errors.Is(err, ErrAuctionHasNoBids), errors.Is(err, ErrReserveNotMet), errors.Is(err, ErrAuctionCancelled), errors.Is(err, ErrProductNotFound),
+			errors.As(err, &stillOpen):
+		default:
+			errs = append(errs, fmt.Errorf("settling auction %s: %w", product.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// closeUnsold records that the auction ended without a sale and commits, so
+// later settlement attempts see it closed, then reports why.
+func closeUnsold(ctx context.Context, tx pgx.Tx, qtx *pgstore.Queries, product *pgstore.Product, reason error) error {
+	if product.Status == ProductLive {
+		if err := transitionProduct(ctx, qtx, product, ProductEnded, nil, product.AuctionEnd); err != nil {
+			return err
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return err
+		}
+	}
+	return reason
+}
+
+func (bs *BidsService) BuyNow(ctx context.Context, productId, buyerId uuid.UUID) (pgstore.AuctionResult, error) {
+	tx, err := bs.pool.Begin(ctx)
+	if err != nil {
+		return pgstore.AuctionResult{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := bs.queries.WithTx(tx)
+	product, err := qtx.GetProductByIdForUpdate(ctx, productId)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return pgstore.Bid{}, err
+			return pgstore.AuctionResult{}, ErrProductNotFound
 		}
+		return pgstore.AuctionResult{}, err
+	}
+	now := time.Now()
+	if err := openAuction(ctx, qtx, &product, now); err != nil {
+		return pgstore.AuctionResult{}, err
 	}
-	highestBid, err := bs.queries.GetHighestBidByProductId(ctx, product_id)
+	if product.AuctionType != AuctionEnglish || product.BuyNowPrice <= 0 {
+		return pgstore.AuctionResult{}, ErrBuyNowUnavailable
+	}
+	highestBid, err := qtx.GetHighestBidByProductId(ctx, productId)
 	if err != nil {
 		if !errors.Is(err, pgx.ErrNoRows) {
-			return pgstore.Bid{}, err
+			return pgstore.AuctionResult{}, err
 		}
 	}
-	if product.Baseprice >= amount || highestBid.BidAmount >= amount {
-		return pgstore.Bid{}, ErrBidIsTooLow
+	if highestBid.BidAmount >= product.BuyNowPrice {
+		return pgstore.AuctionResult{}, ErrBuyNowUnavailable
+	}
+	bid, err := qtx.CreateBid(ctx, pgstore.CreateBidParams{
+		ProductID: productId,
+		BidderID:  buyerId,
+		BidAmount: product.BuyNowPrice,
+	})
+	if err != nil {
+		return pgstore.AuctionResult{}, err
 	}
-	highestBid, err = bs.queries.CreateBid(ctx, pgstore.CreateBidParams{
-		ProductID: product_id,
-		BidderID:  bidder_id,
-		BidAmount: amount,
+	result, err := qtx.CreateAuctionResult(ctx, pgstore.CreateAuctionResultParams{
+		ProductID:   productId,
+		WinnerID:    buyerId,
+		BidID:       bid.ID,
+		HammerPrice: bid.BidAmount,
+		SoldVia:     SaleByBuyNow,
 	})
 	if err != nil {
-		return pgstore.Bid{}, err
+		return pgstore.AuctionResult{}, err
+	}
+	if err := transitionProduct(ctx, qtx, &product, ProductSold, &buyerId, now); err != nil {
+		return pgstore.AuctionResult{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return pgstore.AuctionResult{}, err
 	}
-	return highestBid, nil
+	return result, nil
 }
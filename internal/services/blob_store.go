@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	ErrBlobNotFound   = errors.New("blob not found")
+	ErrInvalidBlobKey = errors.New("invalid blob key")
+)
+
+// BlobStore keeps uploaded files under slash separated keys such as
+// "products/<id>/<image>.jpg". URL returns where clients can fetch a key.
+type BlobStore interface {
+	Put(ctx context.Context, key string, r io.Reader, contentType string) error
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+	URL(key string) string
+}
+
+// LocalBlobStore keeps blobs on the local filesystem under Root and serves
+// them itself under BaseURL.
+type LocalBlobStore struct {
+	Root    string
+	BaseURL string
+}
+
+func NewLocalBlobStore(root, baseURL string) (*LocalBlobStore, error) {
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, err
+	}
+	return &LocalBlobStore{Root: root, BaseURL: strings.TrimSuffix(baseURL, "/")}, nil
+}
+
+func (s *LocalBlobStore) Put(ctx context.Context, key string, r io.Reader, contentType string) error {
+	name, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return err
+	}
+	// Write to a temporary file first so readers never see a partial blob.
+	tmp, err := os.CreateTemp(filepath.Dir(name), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), name)
+}
+
+func (s *LocalBlobStore) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	name, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, ErrBlobNotFound
+		}
+		return nil, err
+	}
+	return f, nil
+}
+
+func (s *LocalBlobStore) Delete(ctx context.Context, key string) error {
+	name, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(name); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (s *LocalBlobStore) URL(key string) string {
+	return s.BaseURL + "/" + key
+}
+
+// ServeHTTP serves the blob named by the request path, which must already
+// have BaseURL stripped.
+func (s *LocalBlobStore) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name, err := s.path(strings.TrimPrefix(r.URL.Path, "/"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil || info.IsDir() {
+		http.NotFound(w, r)
+		return
+	}
+	// Keys are never reused, so the content behind a URL never changes.
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	http.ServeContent(w, r, info.Name(), info.ModTime(), f)
+}
+
+func (s *LocalBlobStore) path(key string) (string, error) {
+	// Dot files are left out so in-progress uploads are never served.
+	if !fs.ValidPath(key) || key == "." || strings.HasPrefix(path.Base(key), ".") {
+		return "", ErrInvalidBlobKey
+	}
+	return filepath.Join(s.Root, filepath.FromSlash(key)), nil
+}
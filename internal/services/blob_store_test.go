@@ -0,0 +1,66 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLocalBlobStore(t *testing.T) {
+	store, err := NewLocalBlobStore(t.TempDir(), "/media/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	key := "products/1/image.png"
+
+	if err := store.Put(ctx, key, strings.NewReader("png bytes"), "image/png"); err != nil {
+		t.Fatal(err)
+	}
+	blob, err := store.Open(ctx, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := io.ReadAll(blob)
+	blob.Close()
+	if string(data) != "png bytes" {
+		t.Fatalf("read %q back", data)
+	}
+	if got := store.URL(key); got != "/media/products/1/image.png" {
+		t.Fatalf("URL = %q", got)
+	}
+
+	rec := httptest.NewRecorder()
+	store.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/products/1/image.png", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "png bytes" {
+		t.Fatalf("served %d %q", rec.Code, rec.Body.String())
+	}
+	rec = httptest.NewRecorder()
+	store.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/products/1", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("serving a directory = %d, want 404", rec.Code)
+	}
+
+	if err := store.Delete(ctx, key); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Open(ctx, key); !errors.Is(err, ErrBlobNotFound) {
+		t.Fatalf("Open after Delete = %v, want ErrBlobNotFound", err)
+	}
+}
+
+func TestLocalBlobStoreRejectsEscapingKeys(t *testing.T) {
+	store, err := NewLocalBlobStore(t.TempDir(), "/media")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"../outside", "/etc/passwd", "a/../../b", "products/.upload-1", ""} {
+		if err := store.Put(context.Background(), key, strings.NewReader("x"), "text/plain"); !errors.Is(err, ErrInvalidBlobKey) {
+			t.Errorf("Put(%q) = %v, want ErrInvalidBlobKey", key, err)
+		}
+	}
+}
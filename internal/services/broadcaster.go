@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"log/slog"
+	"sync"
+)
+
+const subscriptionBufferSize = 512
+
+var ErrSubscriberTooSlow = errors.New("the subscriber is not keeping up with the auction events")
+
+// Broadcaster carries room events to every AuctionRoom of an auction. A room
+// publishes the events its clients must see and hands them to those clients
+// only when they come back through its subscription, so the transport decides
+// which instances are reached.
+type Broadcaster interface {
+	Publish(ctx context.Context, auctionId uuid.UUID, m Message) error
+	Subscribe(auctionId uuid.UUID) (events <-chan Message, unsubscribe func())
+}
+
+// SettlementElector picks the single instance that settles an auction.
+type SettlementElector interface {
+	LeadSettlement(ctx context.Context, auctionId uuid.UUID) (release func(), led bool, err error)
+}
+
+// MemoryBroadcaster delivers events to the rooms of this process only.
+type MemoryBroadcaster struct {
+	mu          sync.Mutex
+	subscribers map[uuid.UUID]map[chan Message]struct{}
+}
+
+func NewMemoryBroadcaster() *MemoryBroadcaster {
+	return &MemoryBroadcaster{
+		subscribers: make(map[uuid.UUID]map[chan Message]struct{}),
+	}
+}
+
+// Publish never blocks, since rooms publish from their own event loop and
+// would otherwise wait on themselves.
+func (b *MemoryBroadcaster) Publish(ctx context.Context, auctionId uuid.UUID, m Message) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var err error
+	for events := range b.subscribers[auctionId] {
+		select {
+		case events <- m:
+		default:
+			slog.Error("Dropped auction event", "auctionID", auctionId, "kind", m.Kind)
+			err = ErrSubscriberTooSlow
+		}
+	}
+	return err
+}
+
+func (b *MemoryBroadcaster) Subscribe(auctionId uuid.UUID) (<-chan Message, func()) {
+	events := make(chan Message, subscriptionBufferSize)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.subscribers[auctionId] == nil {
+		b.subscribers[auctionId] = make(map[chan Message]struct{})
+	}
+	b.subscribers[auctionId][events] = struct{}{}
+
+	return events, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		delete(b.subscribers[auctionId], events)
+		if len(b.subscribers[auctionId]) == 0 {
+			delete(b.subscribers, auctionId)
+		}
+	}
+}
+
+// auctions lists the auctions that have a subscriber in this process.
+func (b *MemoryBroadcaster) auctions() []uuid.UUID {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	auctionIds := make([]uuid.UUID, 0, len(b.subscribers))
+	for auctionId := range b.subscribers {
+		auctionIds = append(auctionIds, auctionId)
+	}
+	return auctionIds
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"github.com/FelipePn10/Gobid/internal/store/pgstore"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"time"
+)
+
+const SaleByDutchAuction = "dutch"
+
+type DutchSchedule struct {
+	StartPrice int64
+	FloorPrice int64
+	Step       int64
+	Interval   time.Duration
+	StartedAt  time.Time
+}
+
+func DutchScheduleFor(product pgstore.Product) (DutchSchedule, bool) {
+	if product.AuctionType != AuctionDutch {
+		return DutchSchedule{}, false
+	}
+	return DutchSchedule{
+		StartPrice: product.DutchStartPrice,
+		FloorPrice: product.DutchFloorPrice,
+		Step:       product.DutchPriceStep,
+		Interval:   time.Duration(product.DutchStepIntervalSeconds) * time.Second,
+		StartedAt:  product.AuctionStart,
+	}, true
+}
+
+func (s DutchSchedule) PriceAt(t time.Time) int64 {
+	if s.Interval <= 0 || t.Before(s.StartedAt) {
+		return s.StartPrice
+	}
+	steps := int64(t.Sub(s.StartedAt) / s.Interval)
+	return max(s.FloorPrice, s.StartPrice-steps*s.Step)
+}
+
+func (bs *BidsService) AcceptDutchPrice(ctx context.Context, productId, buyerId uuid.UUID) (pgstore.AuctionResult, error) {
+	tx, err := bs.pool.Begin(ctx)
+	if err != nil {
+		return pgstore.AuctionResult{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := bs.queries.WithTx(tx)
+	product, err := qtx.GetProductByIdForUpdate(ctx, productId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return pgstore.AuctionResult{}, ErrProductNotFound
+		}
+		return pgstore.AuctionResult{}, err
+	}
+	schedule, ok := DutchScheduleFor(product)
+	if !ok {
+		return pgstore.AuctionResult{}, ErrWrongAuctionType
+	}
+	now := time.Now()
+	if err := openAuction(ctx, qtx, &product, now); err != nil {
+		return pgstore.AuctionResult{}, err
+	}
+	bid, err := qtx.CreateBid(ctx, pgstore.CreateBidParams{
+		ProductID: productId,
+		BidderID:  buyerId,
+		BidAmount: schedule.PriceAt(now),
+	})
+	if err != nil {
+		return pgstore.AuctionResult{}, err
+	}
+	result, err := qtx.CreateAuctionResult(ctx, pgstore.CreateAuctionResultParams{
+		ProductID:   productId,
+		WinnerID:    buyerId,
+		BidID:       bid.ID,
+		HammerPrice: bid.BidAmount,
+		SoldVia:     SaleByDutchAuction,
+	})
+	if err != nil {
+		return pgstore.AuctionResult{}, err
+	}
+	if err := transitionProduct(ctx, qtx, &product, ProductSold, &buyerId, now); err != nil {
+		return pgstore.AuctionResult{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return pgstore.AuctionResult{}, err
+	}
+	return result, nil
+}
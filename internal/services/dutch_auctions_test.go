@@ -0,0 +1,46 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDutchSchedulePriceAt(t *testing.T) {
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	schedule := DutchSchedule{
+		StartPrice: 100_00,
+		FloorPrice: 70_00,
+		Step:       10_00,
+		Interval:   time.Minute,
+		StartedAt:  start,
+	}
+	tests := []struct {
+		after time.Duration
+		want  int64
+	}{
+		{-time.Second, 100_00},
+		{0, 100_00},
+		{time.Minute - time.Nanosecond, 100_00},
+		{time.Minute, 90_00},
+		{2*time.Minute + 30*time.Second, 80_00},
+		{3 * time.Minute, 70_00},
+		// The price stops at the floor instead of dropping a partial step.
+		{4 * time.Minute, 70_00},
+		{24 * time.Hour, 70_00},
+	}
+	for _, tt := range tests {
+		if got := schedule.PriceAt(start.Add(tt.after)); got != tt.want {
+			t.Errorf("PriceAt(start + %v) = %d, want %d", tt.after, got, tt.want)
+		}
+	}
+
+	// A floor between steps is reached exactly, not skipped.
+	schedule.FloorPrice = 75_00
+	if got := schedule.PriceAt(start.Add(3 * time.Minute)); got != 75_00 {
+		t.Errorf("PriceAt past a floor between steps = %d, want 75_00", got)
+	}
+	schedule.Interval = 0
+	if got := schedule.PriceAt(start.Add(time.Hour)); got != 100_00 {
+		t.Errorf("PriceAt without an interval = %d, want the start price", got)
+	}
+}
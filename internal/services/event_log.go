@@ -0,0 +1,83 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+const replayBufferSize = 256
+
+var ErrInvalidEventCursor = errors.New("invalid event cursor")
+
+// EventCursor names the last event a client saw. Every run of a room numbers
+// its events from one, on each instance and after every restart, so a
+// sequence number only means something next to the epoch of the run that
+// assigned it. An empty Epoch stands for whichever run the room is in.
+type EventCursor struct {
+	Epoch string
+	Seq   uint64
+}
+
+func (c EventCursor) String() string {
+	return c.Epoch + ":" + strconv.FormatUint(c.Seq, 10)
+}
+
+// ParseEventCursor reads a cursor in the epoch:seq form rooms send.
+func ParseEventCursor(raw string) (EventCursor, error) {
+	epoch, rawSeq, ok := strings.Cut(raw, ":")
+	if !ok || epoch == "" {
+		return EventCursor{}, ErrInvalidEventCursor
+	}
+	seq, err := strconv.ParseUint(rawSeq, 10, 64)
+	if err != nil {
+		return EventCursor{}, ErrInvalidEventCursor
+	}
+	return EventCursor{Epoch: epoch, Seq: seq}, nil
+}
+
+func newEpoch() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
+
+// eventLog keeps the most recent room events in a ring buffer so reconnecting
+// clients can catch up on what they missed.
+type eventLog struct {
+	events []Message
+	next   int
+}
+
+func newEventLog(size int) *eventLog {
+	return &eventLog{events: make([]Message, 0, size)}
+}
+
+func (l *eventLog) add(m Message) {
+	if len(l.events) < cap(l.events) {
+		l.events = append(l.events, m)
+		return
+	}
+	l.events[l.next] = m
+	l.next = (l.next + 1) % len(l.events)
+}
+
+// since returns the buffered events after seq in order, and whether they are
+// all the events that followed it.
+func (l *eventLog) since(seq uint64) ([]Message, bool) {
+	var missed []Message
+	for i := range l.events {
+		m := l.events[(l.next+i)%len(l.events)]
+		if m.Seq > seq {
+			missed = append(missed, m)
+		}
+	}
+	if len(missed) == 0 {
+		return nil, true
+	}
+	return missed, missed[0].Seq == seq+1
+}
@@ -0,0 +1,38 @@
+package services
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestEventLogSince(t *testing.T) {
+	log := newEventLog(3)
+	for seq := uint64(1); seq <= 5; seq++ {
+		log.add(Message{Seq: seq})
+	}
+
+	missed, complete := log.since(3)
+	if !complete || len(missed) != 2 || missed[0].Seq != 4 || missed[1].Seq != 5 {
+		t.Errorf("since(3) = %+v, %v", missed, complete)
+	}
+	missed, complete = log.since(1)
+	if complete || len(missed) != 3 || missed[0].Seq != 3 {
+		t.Errorf("since(1) = %+v, %v, expected an incomplete replay from 3", missed, complete)
+	}
+	if missed, complete = log.since(5); !complete || len(missed) != 0 {
+		t.Errorf("since(5) = %+v, %v", missed, complete)
+	}
+}
+
+func TestParseEventCursor(t *testing.T) {
+	cursor := EventCursor{Epoch: "3f2a", Seq: 42}
+	got, err := ParseEventCursor(cursor.String())
+	if err != nil || got != cursor {
+		t.Fatalf("ParseEventCursor(%q) = %+v, %v, want %+v", cursor.String(), got, err, cursor)
+	}
+	for _, raw := range []string{"42", ":42", "3f2a:", "3f2a:-1", "3f2a:x"} {
+		if _, err := ParseEventCursor(raw); !errors.Is(err, ErrInvalidEventCursor) {
+			t.Errorf("ParseEventCursor(%q) = %v, want ErrInvalidEventCursor", raw, err)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// StreamEventLoop writes the room's messages to w as Server-Sent Events for
+// clients that cannot open a websocket. The stream is read-only and ends when
+// ctx is done or the auction closes.
+func (c *Client) StreamEventLoop(ctx context.Context, w http.ResponseWriter) error {
+	rc := http.NewResponseController(w)
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.unregister()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	if err := rc.Flush(); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case message := <-c.Send:
+			if err := writeEvent(w, message); err != nil {
+				return err
+			}
+			if err := rc.Flush(); err != nil {
+				return err
+			}
+			if closesRoom(message.Kind) {
+				return nil
+			}
+		case <-ticker.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return err
+			}
+			if err := rc.Flush(); err != nil {
+				return err
+			}
+		case <-c.Room.Done():
+			// Hand over whatever the room sent before closing.
+			for {
+				select {
+				case message := <-c.Send:
+					if err := writeEvent(w, message); err != nil {
+						return err
+					}
+				default:
+					return rc.Flush()
+				}
+			}
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func writeEvent(w http.ResponseWriter, m Message) error {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	if m.Cursor != "" {
+		if _, err := fmt.Fprintf(w, "id: %s\n", m.Cursor); err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprintf(w, "data: %s\n\n", data)
+	return err
+}
@@ -0,0 +1,40 @@
+package services
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStreamEventLoopWritesEventsUntilTheAuctionCloses(t *testing.T) {
+	b := newFakeBroadcaster()
+	room, _ := startTestRoom(t, b)
+
+	client := NewClient(room, uuid.New(), nil)
+	room.Register <- client
+	b.events <- Message{Kind: NewBidPlaced, Amount: NewMoney(10_00, DefaultCurrency), UserId: uuid.New()}
+	b.events <- Message{Kind: SoldViaBuyNow, Amount: NewMoney(50_00, DefaultCurrency), UserId: uuid.New()}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	w := httptest.NewRecorder()
+	if err := client.StreamEventLoop(ctx, w); err != nil {
+		t.Fatal(err)
+	}
+
+	body := w.Body.String()
+	if got := w.Header().Get("Content-Type"); got != "text/event-stream" {
+		t.Errorf("unexpected content type %q", got)
+	}
+	for _, want := range []string{
+		"id: " + room.epoch + ":1\ndata: {", `"amount":"10.00"`,
+		"id: " + room.epoch + ":2\ndata: {", `"amount":"50.00"`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("stream is missing %q:\n%s", want, body)
+		}
+	}
+}
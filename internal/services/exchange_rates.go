@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"os"
+)
+
+var ErrRateUnavailable = errors.New("no exchange rate available for this currency pair")
+
+// ExchangeRateProvider returns how many units of `to` one unit of `from` buys.
+// Rooms call it from their event loop, so implementations backed by a remote
+// service should cache their rates.
+type ExchangeRateProvider interface {
+	Rate(ctx context.Context, from, to string) (*big.Rat, error)
+}
+
+// StaticRates holds fixed rates quoted against a single base currency.
+type StaticRates struct {
+	base  string
+	rates map[string]*big.Rat
+}
+
+func NewStaticRates(base string, rates map[string]string) (*StaticRates, error) {
+	sr := &StaticRates{
+		base:  base,
+		rates: map[string]*big.Rat{base: big.NewRat(1, 1)},
+	}
+	for currency, value := range rates {
+		rate, ok := new(big.Rat).SetString(value)
+		if !ok || rate.Sign() <= 0 {
+			return nil, fmt.Errorf("invalid exchange rate %q for %s", value, currency)
+		}
+		sr.rates[currency] = rate
+	}
+	return sr, nil
+}
+
+// LoadRatesFile reads rates from a JSON file shaped like
+// {"base": "USD", "rates": {"EUR": "0.92", "BRL": "5.10"}}.
+func LoadRatesFile(path string) (*StaticRates, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file struct {
+		Base  string            `json:"base"`
+		Rates map[string]string `json:"rates"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to decode exchange rates file: %w", err)
+	}
+	return NewStaticRates(file.Base, file.Rates)
+}
+
+func (sr *StaticRates) Rate(ctx context.Context, from, to string) (*big.Rat, error) {
+	fromRate, ok := sr.rates[from]
+	if !ok {
+		return nil, ErrRateUnavailable
+	}
+	toRate, ok := sr.rates[to]
+	if !ok {
+		return nil, ErrRateUnavailable
+	}
+	return new(big.Rat).Quo(toRate, fromRate), nil
+}
+
+// ConvertMoney converts an amount, rounding half away from zero to the
+// nearest minor unit of to.
+func ConvertMoney(ctx context.Context, rates ExchangeRateProvider, amount Money, to string) (Money, error) {
+	if amount.Currency == to {
+		return amount, nil
+	}
+	rate, err := rates.Rate(ctx, amount.Currency, to)
+	if err != nil {
+		return Money{}, err
+	}
+	converted := new(big.Rat).Mul(new(big.Rat).SetInt64(amount.Amount), rate)
+	// Rates are quoted per unit, so account for currencies whose minor units
+	// differ, such as USD cents and whole JPY.
+	converted.Mul(converted, new(big.Rat).SetFrac(pow10(decimalsOf(to)), pow10(decimalsOf(amount.Currency))))
+	num, denom := converted.Num(), converted.Denom()
+	quo, rem := new(big.Int).QuoRem(num, denom, new(big.Int))
+	if new(big.Int).Mul(new(big.Int).Abs(rem), big.NewInt(2)).Cmp(denom) >= 0 {
+		quo.Add(quo, big.NewInt(int64(num.Sign())))
+	}
+	if !quo.IsInt64() {
+		return Money{}, ErrInvalidAmount
+	}
+	return Money{Amount: quo.Int64(), Currency: to}, nil
+}
+
+// DisplayAmount converts amount for display, returning nil when no
+// conversion was asked for or none could be made.
+func DisplayAmount(ctx context.Context, rates ExchangeRateProvider, amount *Money, currency string) *Money {
+	if rates == nil || amount == nil || currency == "" || currency == amount.Currency {
+		return nil
+	}
+	converted, err := ConvertMoney(ctx, rates, *amount, currency)
+	if err != nil {
+		slog.Warn("Failed to convert amount", "from", amount.Currency, "to", currency, "error", err)
+		return nil
+	}
+	return &converted
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
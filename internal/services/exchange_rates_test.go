@@ -0,0 +1,43 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestConvertMoney(t *testing.T) {
+	rates, err := NewStaticRates("USD", map[string]string{"EUR": "0.92", "BRL": "5.1", "JPY": "150"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	cases := []struct {
+		amount Money
+		to     string
+		want   int64
+	}{
+		{Money{Amount: 100_00, Currency: "USD"}, "EUR", 92_00},
+		{Money{Amount: 1, Currency: "USD"}, "EUR", 1},
+		{Money{Amount: 510_00, Currency: "BRL"}, "USD", 100_00},
+		{Money{Amount: 92_00, Currency: "EUR"}, "BRL", 510_00},
+		{Money{Amount: 12_34, Currency: "EUR"}, "EUR", 12_34},
+		{Money{Amount: 100_00, Currency: "USD"}, "JPY", 15000},
+		{Money{Amount: 15000, Currency: "JPY"}, "USD", 100_00},
+		{Money{Amount: 1, Currency: "JPY"}, "USD", 1},
+	}
+	for _, c := range cases {
+		got, err := ConvertMoney(ctx, rates, c.amount, c.to)
+		if err != nil {
+			t.Fatalf("converting %v to %s: %v", c.amount, c.to, err)
+		}
+		if got.Amount != c.want || got.Currency != c.to {
+			t.Errorf("converting %v %s to %s: got %v %s, want %d", c.amount, c.amount.Currency, c.to, got, got.Currency, c.want)
+		}
+	}
+
+	if _, err := ConvertMoney(ctx, rates, Money{Amount: 1, Currency: "USD"}, "GBP"); !errors.Is(err, ErrRateUnavailable) {
+		t.Errorf("expected ErrRateUnavailable, got %v", err)
+	}
+}
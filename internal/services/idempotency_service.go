@@ -0,0 +1,110 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/FelipePn10/Gobid/internal/store/pgstore"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"time"
+)
+
+const (
+	idempotencyKeyTTL = 24 * time.Hour
+	// A pending key is held for at most idempotencyLease, long enough for
+	// any request to finish. After that a retry takes the key over, in case
+	// the request that claimed it died without releasing it.
+	idempotencyLease = time.Minute
+)
+
+var (
+	ErrIdempotencyKeyReused     = errors.New("the idempotency key was already used for a different request")
+	ErrIdempotentRequestPending = errors.New("a request with this idempotency key is still being processed")
+)
+
+type IdempotencyService struct {
+	pool    *pgxpool.Pool
+	queries *pgstore.Queries
+}
+
+// StoredResponse is the outcome of a request that was already handled under
+// an idempotency key.
+type StoredResponse struct {
+	StatusCode int
+	Body       []byte
+}
+
+func NewIdempotencyService(pool *pgxpool.Pool) IdempotencyService {
+	return IdempotencyService{
+		pool:    pool,
+		queries: pgstore.New(pool),
+	}
+}
+
+// Begin claims key for the request identified by requestHash. It returns the
+// stored response when the request was already handled, or nil when the
+// caller now owns the key and must Complete or Release it.
+func (is *IdempotencyService) Begin(ctx context.Context, userId uuid.UUID, key string, requestHash []byte) (*StoredResponse, error) {
+	now := time.Now()
+	err := is.queries.DeleteExpiredIdempotencyKey(ctx, pgstore.DeleteExpiredIdempotencyKeyParams{
+		UserID:        userId,
+		Key:           key,
+		ExpiredBefore: now.Add(-idempotencyKeyTTL),
+	})
+	if err != nil {
+		return nil, err
+	}
+	created, err := is.queries.CreateIdempotencyKey(ctx, pgstore.CreateIdempotencyKeyParams{
+		UserID:      userId,
+		Key:         key,
+		RequestHash: requestHash,
+		LockedUntil: now.Add(idempotencyLease),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if created == 1 {
+		return nil, nil
+	}
+
+	stored, err := is.queries.GetIdempotencyKey(ctx, pgstore.GetIdempotencyKeyParams{UserID: userId, Key: key})
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(stored.RequestHash, requestHash) {
+		return nil, ErrIdempotencyKeyReused
+	}
+	if !stored.StatusCode.Valid {
+		takenOver, err := is.queries.TakeOverIdempotencyKey(ctx, pgstore.TakeOverIdempotencyKeyParams{
+			UserID:      userId,
+			Key:         key,
+			LockedUntil: now.Add(idempotencyLease),
+			Now:         now,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if takenOver == 1 {
+			return nil, nil
+		}
+		return nil, ErrIdempotentRequestPending
+	}
+	return &StoredResponse{StatusCode: int(stored.StatusCode.Int32), Body: stored.ResponseBody}, nil
+}
+
+func (is *IdempotencyService) Complete(ctx context.Context, userId uuid.UUID, key string, response StoredResponse) error {
+	return is.queries.CompleteIdempotencyKey(ctx, pgstore.CompleteIdempotencyKeyParams{
+		UserID:       userId,
+		Key:          key,
+		StatusCode:   pgtype.Int4{Int32: int32(response.StatusCode), Valid: true},
+		ResponseBody: response.Body,
+	})
+}
+
+// Release frees key so the request can be retried, for outcomes that should
+// not be replayed such as server errors.
+func (is *IdempotencyService) Release(ctx context.Context, userId uuid.UUID, key string) error {
+	return is.queries.DeleteIdempotencyKey(ctx, pgstore.DeleteIdempotencyKeyParams{UserID: userId, Key: key})
+}
@@ -0,0 +1,57 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"github.com/FelipePn10/Gobid/internal/store/pgstore"
+	"testing"
+)
+
+func TestIdempotencyKeyReplaysStoredResponse(t *testing.T) {
+	pool := testPool(t)
+	userId := createTestUser(t, pgstore.New(pool))
+	is := NewIdempotencyService(pool)
+	ctx := context.Background()
+
+	stored, err := is.Begin(ctx, userId, "retry-me", []byte("request"))
+	if err != nil || stored != nil {
+		t.Fatalf("the first request should own the key, got %v, %v", stored, err)
+	}
+	if _, err := is.Begin(ctx, userId, "retry-me", []byte("request")); !errors.Is(err, ErrIdempotentRequestPending) {
+		t.Fatalf("expected ErrIdempotentRequestPending, got %v", err)
+	}
+	response := StoredResponse{StatusCode: 201, Body: []byte(`{"message":"ok"}`)}
+	if err := is.Complete(ctx, userId, "retry-me", response); err != nil {
+		t.Fatal(err)
+	}
+
+	stored, err = is.Begin(ctx, userId, "retry-me", []byte("request"))
+	if err != nil || stored == nil || stored.StatusCode != 201 || string(stored.Body) != string(response.Body) {
+		t.Fatalf("expected the stored response, got %+v, %v", stored, err)
+	}
+	if _, err := is.Begin(ctx, userId, "retry-me", []byte("another request")); !errors.Is(err, ErrIdempotencyKeyReused) {
+		t.Fatalf("expected ErrIdempotencyKeyReused, got %v", err)
+	}
+}
+
+func TestIdempotencyKeyLeaseExpires(t *testing.T) {
+	pool := testPool(t)
+	userId := createTestUser(t, pgstore.New(pool))
+	is := NewIdempotencyService(pool)
+	ctx := context.Background()
+
+	if stored, err := is.Begin(ctx, userId, "abandoned", []byte("request")); err != nil || stored != nil {
+		t.Fatalf("the first request should own the key, got %v, %v", stored, err)
+	}
+	// The request that claimed the key died without releasing it.
+	_, err := pool.Exec(ctx, "UPDATE idempotency_keys SET locked_until = now() - interval '1 second' WHERE user_id = $1 AND key = $2", userId, "abandoned")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored, err := is.Begin(ctx, userId, "abandoned", []byte("request")); err != nil || stored != nil {
+		t.Fatalf("a retry should take over the stale key, got %v, %v", stored, err)
+	}
+	if _, err := is.Begin(ctx, userId, "abandoned", []byte("request")); !errors.Is(err, ErrIdempotentRequestPending) {
+		t.Fatalf("the key taken over should be pending again, got %v", err)
+	}
+}
@@ -0,0 +1,184 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const DefaultCurrency = "USD"
+
+// currencyDecimals holds how many decimal places each supported currency
+// has. Amounts are stored in the smallest unit of their currency, so 1250 is
+// 12.50 USD but 1250 JPY and 1.250 KWD.
+var currencyDecimals = map[string]int{
+	"USD": 2,
+	"EUR": 2,
+	"BRL": 2,
+	"GBP": 2,
+	"JPY": 0,
+	"KWD": 3,
+}
+
+var (
+	ErrInvalidAmount       = errors.New("invalid monetary amount")
+	ErrCurrencyMismatch    = errors.New("the amount is not in the currency of the listing")
+	ErrUnsupportedCurrency = errors.New("unsupported currency")
+)
+
+// Money is an amount in minor units of Currency. An empty Currency means the
+// amount was decoded before its currency was known, in which case it was
+// read with the decimals of DefaultCurrency; see In.
+type Money struct {
+	Amount   int64
+	Currency string
+}
+
+func NewMoney(amount int64, currency string) *Money {
+	return &Money{Amount: amount, Currency: currency}
+}
+
+func IsSupportedCurrency(code string) bool {
+	_, ok := currencyDecimals[code]
+	return ok
+}
+
+// SupportedCurrencies lists the supported currency codes in order.
+func SupportedCurrencies() []string {
+	return slices.Sorted(maps.Keys(currencyDecimals))
+}
+
+// CurrencyDecimals reports how many decimal places amounts in currency have.
+func CurrencyDecimals(currency string) int {
+	return decimalsOf(currency)
+}
+
+func decimalsOf(currency string) int {
+	if decimals, ok := currencyDecimals[currency]; ok {
+		return decimals
+	}
+	return currencyDecimals[DefaultCurrency]
+}
+
+// ParseMoney reads a decimal such as "1250" or "12.50" without going through
+// floating point, rejecting more decimal places than currency has.
+func ParseMoney(value, currency string) (Money, error) {
+	decimals := decimalsOf(currency)
+	value = strings.TrimSpace(value)
+	negative := strings.HasPrefix(value, "-")
+	value = strings.TrimPrefix(value, "-")
+
+	units, fraction, hasFraction := strings.Cut(value, ".")
+	if units == "" || len(fraction) > decimals || (hasFraction && fraction == "") {
+		return Money{}, ErrInvalidAmount
+	}
+	for _, r := range units + fraction {
+		if r < '0' || r > '9' {
+			return Money{}, ErrInvalidAmount
+		}
+	}
+	amount, err := strconv.ParseInt(units+fraction+strings.Repeat("0", decimals-len(fraction)), 10, 64)
+	if err != nil {
+		return Money{}, ErrInvalidAmount
+	}
+	if negative {
+		amount = -amount
+	}
+	return Money{Amount: amount, Currency: currency}, nil
+}
+
+// In returns m in currency. An amount without a currency is rescaled from
+// the decimals it was read with, failing if currency cannot represent it.
+func (m Money) In(currency string) (Money, error) {
+	switch m.Currency {
+	case currency:
+		return m, nil
+	case "":
+	default:
+		return Money{}, ErrCurrencyMismatch
+	}
+	amount := m.Amount
+	for from, to := decimalsOf(""), decimalsOf(currency); from != to; {
+		if from < to {
+			if amount > math.MaxInt64/10 || amount < math.MinInt64/10 {
+				return Money{}, ErrInvalidAmount
+			}
+			amount, from = amount*10, from+1
+		} else {
+			if amount%10 != 0 {
+				return Money{}, ErrInvalidAmount
+			}
+			amount, from = amount/10, from-1
+		}
+	}
+	return Money{Amount: amount, Currency: currency}, nil
+}
+
+func (m Money) String() string {
+	sign := ""
+	amount := uint64(m.Amount)
+	if m.Amount < 0 {
+		sign, amount = "-", -amount
+	}
+	decimals := decimalsOf(m.Currency)
+	if decimals == 0 {
+		return fmt.Sprintf("%s%d", sign, amount)
+	}
+	unit := uint64(math.Pow10(decimals))
+	return fmt.Sprintf("%s%d.%0*d", sign, amount/unit, decimals, amount%unit)
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Amount   string `json:"amount"`
+		Currency string `json:"currency,omitempty"`
+	}{
+		Amount:   m.String(),
+		Currency: m.Currency,
+	})
+}
+
+// UnmarshalJSON accepts the object produced by MarshalJSON as well as a bare
+// decimal string or JSON number, which is read from its literal text.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	switch {
+	case bytes.Equal(data, []byte("null")):
+		return nil
+	case bytes.HasPrefix(data, []byte("{")):
+		var v struct {
+			Amount   json.RawMessage `json:"amount"`
+			Currency string          `json:"currency"`
+		}
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		// The currency decides how many decimals the amount may have.
+		m.Currency = v.Currency
+		return m.UnmarshalJSON(v.Amount)
+	case bytes.HasPrefix(data, []byte(`"`)):
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		parsed, err := ParseMoney(s, m.Currency)
+		if err != nil {
+			return err
+		}
+		*m = parsed
+		return nil
+	default:
+		parsed, err := ParseMoney(string(data), m.Currency)
+		if err != nil {
+			return err
+		}
+		*m = parsed
+		return nil
+	}
+}
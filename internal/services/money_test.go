@@ -0,0 +1,140 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		value, currency string
+		want            int64
+		wantErr         bool
+	}{
+		{value: "12.50", currency: "USD", want: 12_50},
+		{value: "12", currency: "USD", want: 12_00},
+		{value: "0.5", currency: "EUR", want: 50},
+		{value: " 7.05 ", currency: "GBP", want: 7_05},
+		{value: "1250", currency: "JPY", want: 1250},
+		{value: "12.5", currency: "JPY", wantErr: true},
+		{value: "1.250", currency: "KWD", want: 1250},
+		{value: "1.25", currency: "KWD", want: 1250},
+		{value: "1.2505", currency: "KWD", wantErr: true},
+		{value: "12.505", currency: "USD", wantErr: true},
+		{value: "-3.20", currency: "USD", want: -3_20},
+		{value: "92233720368547758.07", currency: "USD", want: 9223372036854775807},
+		{value: "92233720368547758.08", currency: "USD", wantErr: true},
+		{value: "9223372036854775.808", currency: "KWD", wantErr: true},
+		{value: "12.", currency: "USD", wantErr: true},
+		{value: ".50", currency: "USD", wantErr: true},
+		{value: "1e3", currency: "USD", wantErr: true},
+		{value: "+5", currency: "USD", wantErr: true},
+		{value: "--5", currency: "USD", wantErr: true},
+		{value: "", currency: "USD", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseMoney(tt.value, tt.currency)
+		if tt.wantErr {
+			if !errors.Is(err, ErrInvalidAmount) {
+				t.Errorf("ParseMoney(%q, %s) = %v, %v, want ErrInvalidAmount", tt.value, tt.currency, got.Amount, err)
+			}
+			continue
+		}
+		if err != nil || got.Amount != tt.want || got.Currency != tt.currency {
+			t.Errorf("ParseMoney(%q, %s) = %v %s, %v, want %d", tt.value, tt.currency, got.Amount, got.Currency, err, tt.want)
+		}
+	}
+}
+
+func TestMoneyString(t *testing.T) {
+	tests := []struct {
+		money Money
+		want  string
+	}{
+		{Money{Amount: 12_50, Currency: "USD"}, "12.50"},
+		{Money{Amount: 5, Currency: "EUR"}, "0.05"},
+		{Money{Amount: -3_20, Currency: "USD"}, "-3.20"},
+		{Money{Amount: 1250, Currency: "JPY"}, "1250"},
+		{Money{Amount: 1250, Currency: "KWD"}, "1.250"},
+		{Money{Amount: 7, Currency: "KWD"}, "0.007"},
+		{Money{Amount: -9223372036854775808, Currency: "USD"}, "-92233720368547758.08"},
+	}
+	for _, tt := range tests {
+		if got := tt.money.String(); got != tt.want {
+			t.Errorf("%d %s = %q, want %q", tt.money.Amount, tt.money.Currency, got, tt.want)
+		}
+	}
+}
+
+func TestMoneyJSON(t *testing.T) {
+	for _, money := range []Money{
+		{Amount: 12_50, Currency: "USD"},
+		{Amount: -1, Currency: "EUR"},
+		{Amount: 1250, Currency: "JPY"},
+		{Amount: 1250, Currency: "KWD"},
+	} {
+		data, err := json.Marshal(money)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var decoded Money
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("decoding %s: %v", data, err)
+		}
+		if decoded != money {
+			t.Errorf("%s decoded as %+v, want %+v", data, decoded, money)
+		}
+	}
+
+	tests := []struct {
+		input string
+		want  Money
+	}{
+		{`"12.50"`, Money{Amount: 12_50}},
+		{`12.5`, Money{Amount: 12_50}},
+		{`{"amount": "1.250", "currency": "KWD"}`, Money{Amount: 1250, Currency: "KWD"}},
+		{`{"amount": 1250, "currency": "JPY"}`, Money{Amount: 1250, Currency: "JPY"}},
+	}
+	for _, tt := range tests {
+		var got Money
+		if err := json.Unmarshal([]byte(tt.input), &got); err != nil || got != tt.want {
+			t.Errorf("decoding %s = %+v, %v, want %+v", tt.input, got, err, tt.want)
+		}
+	}
+	for _, input := range []string{`"12.505"`, `{"amount": "12.5", "currency": "JPY"}`, `true`} {
+		var got Money
+		if err := json.Unmarshal([]byte(input), &got); err == nil {
+			t.Errorf("decoding %s = %+v, want an error", input, got)
+		}
+	}
+}
+
+func TestMoneyIn(t *testing.T) {
+	tests := []struct {
+		money    Money
+		currency string
+		want     int64
+		wantErr  error
+	}{
+		{Money{Amount: 12_50, Currency: "USD"}, "USD", 12_50, nil},
+		{Money{Amount: 12_50}, "EUR", 12_50, nil},
+		{Money{Amount: 1250_00}, "JPY", 1250, nil},
+		{Money{Amount: 12_50}, "JPY", 0, ErrInvalidAmount},
+		{Money{Amount: 1_25}, "KWD", 1250, nil},
+		{Money{Amount: 9223372036854775807}, "KWD", 0, ErrInvalidAmount},
+		{Money{Amount: 12_50, Currency: "EUR"}, "USD", 0, ErrCurrencyMismatch},
+	}
+	for _, tt := range tests {
+		got, err := tt.money.In(tt.currency)
+		if tt.wantErr != nil {
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("%+v in %s = %v, want %v", tt.money, tt.currency, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || got.Amount != tt.want || got.Currency != tt.currency {
+			t.Errorf("%+v in %s = %+v, %v, want %d", tt.money, tt.currency, got, err, tt.want)
+		}
+	}
+}
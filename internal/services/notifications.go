@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"github.com/FelipePn10/Gobid/internal/store/pgstore"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"slices"
+	"time"
+)
+
+// NotificationNewListing tells a user that a listing in a category or with a
+// tag they follow has been published.
+const NotificationNewListing = "new_listing"
+
+const notificationPageSize = 50
+
+type NotificationService struct {
+	pool    *pgxpool.Pool
+	queries *pgstore.Queries
+}
+
+func NewNotificationService(pool *pgxpool.Pool) NotificationService {
+	return NotificationService{
+		pool:    pool,
+		queries: pgstore.New(pool),
+	}
+}
+
+type Notification struct {
+	ID          uuid.UUID  `json:"id"`
+	Kind        string     `json:"kind"`
+	ProductID   uuid.UUID  `json:"product_id"`
+	ProductName string     `json:"product_name"`
+	CreatedAt   time.Time  `json:"created_at"`
+	ReadAt      *time.Time `json:"read_at,omitempty"`
+}
+
+// ListNotifications returns the user's most recent notifications, newest
+// first.
+func (ns *NotificationService) ListNotifications(ctx context.Context, userId uuid.UUID) ([]Notification, error) {
+	rows, err := ns.queries.ListNotificationsByUserId(ctx, pgstore.ListNotificationsByUserIdParams{
+		UserID: userId,
+		Limit:  notificationPageSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+	notifications := make([]Notification, 0, len(rows))
+	for _, row := range rows {
+		notification := Notification{
+			ID:          row.ID,
+			Kind:        row.Kind,
+			ProductID:   row.ProductID,
+			ProductName: row.ProductName,
+			CreatedAt:   row.CreatedAt,
+		}
+		if row.ReadAt.Valid {
+			notification.ReadAt = &row.ReadAt.Time
+		}
+		notifications = append(notifications, notification)
+	}
+	return notifications, nil
+}
+
+func (ns *NotificationService) MarkNotificationsRead(ctx context.Context, userId uuid.UUID) error {
+	return ns.queries.MarkNotificationsRead(ctx, userId)
+}
+
+// notifySubscribers tells the users following the product's category, any
+// category above it, or one of its tags that it has been listed. The seller
+// is not told about their own listing.
+func notifySubscribers(ctx context.Context, qtx *pgstore.Queries, productId, sellerId uuid.UUID) error {
+	subscribers, err := qtx.ListSubscribersForProduct(ctx, productId)
+	if err != nil {
+		return err
+	}
+	subscribers = slices.DeleteFunc(subscribers, func(userId uuid.UUID) bool { return userId == sellerId })
+	if len(subscribers) == 0 {
+		return nil
+	}
+	_, err = qtx.CreateNotifications(ctx, pgstore.CreateNotificationsParams{
+		UserIds:   subscribers,
+		ProductID: productId,
+		Kind:      NotificationNewListing,
+	})
+	return err
+}
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"context"
+	"github.com/FelipePn10/Gobid/internal/store/pgstore"
+	"github.com/google/uuid"
+	"testing"
+	"time"
+)
+
+func TestPublishingNotifiesTagSubscribers(t *testing.T) {
+	pool := testPool(t)
+	q := pgstore.New(pool)
+	ps := NewProductsService(pool)
+	ts := NewTaxonomyService(pool)
+	ns := NewNotificationService(pool)
+	ctx := context.Background()
+
+	sellerId, subscriberId := createTestUser(t, q), createTestUser(t, q)
+	tag := "tag-" + uuid.NewString()[:8]
+	productId, err := ps.CreateProduct(ctx, sellerId, "draft product", "a draft product that is published later on",
+		100_00, time.Now().Add(3*time.Hour), AuctionSettings{
+			Type:      AuctionEnglish,
+			Currency:  DefaultCurrency,
+			Start:     time.Now(),
+			Increment: DefaultIncrementPolicy(DefaultCurrency),
+			Draft:     true,
+		}, ProductClassification{Tags: []string{tag}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, userId := range []uuid.UUID{sellerId, subscriberId} {
+		if err := ts.SubscribeToTag(ctx, userId, tag); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := ps.PublishProduct(ctx, productId, sellerId); err != nil {
+		t.Fatal(err)
+	}
+	notifications, err := ns.ListNotifications(ctx, subscriberId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(notifications) != 1 || notifications[0].ProductID != productId || notifications[0].Kind != NotificationNewListing {
+		t.Fatalf("subscriber notifications = %+v, want the new listing", notifications)
+	}
+	notifications, err = ns.ListNotifications(ctx, sellerId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(notifications) != 0 {
+		t.Fatalf("the seller was notified of their own listing: %+v", notifications)
+	}
+}
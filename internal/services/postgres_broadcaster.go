@@ -0,0 +1,184 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/FelipePn10/Gobid/internal/store/pgstore"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"log/slog"
+	"time"
+)
+
+const (
+	auctionEventsChannel = "auction_events"
+	listenRetryDelay     = 5 * time.Second
+)
+
+// PostgresBroadcaster relays room events between API instances through
+// Postgres LISTEN/NOTIFY, and elects a single instance to settle each auction
+// with an advisory lock.
+type PostgresBroadcaster struct {
+	pool     *pgxpool.Pool
+	queries  *pgstore.Queries
+	local    *MemoryBroadcaster
+	instance string
+}
+
+// auctionEvent carries the user a message is about next to it, since
+// messages leave that out of their JSON for clients, and the instance that
+// published it, which has already delivered it locally.
+type auctionEvent struct {
+	Instance  string    `json:"instance"`
+	AuctionId uuid.UUID `json:"auctionId"`
+	UserId    uuid.UUID `json:"userId"`
+	Message   Message   `json:"message"`
+}
+
+func NewPostgresBroadcaster(pool *pgxpool.Pool) *PostgresBroadcaster {
+	return &PostgresBroadcaster{
+		pool:     pool,
+		queries:  pgstore.New(pool),
+		local:    NewMemoryBroadcaster(),
+		instance: newEpoch(),
+	}
+}
+
+// Publish delivers m to the rooms of this instance right away and notifies
+// the other instances. It only fails when the local rooms missed m, since a
+// room that sees an error applies the event itself.
+func (b *PostgresBroadcaster) Publish(ctx context.Context, auctionId uuid.UUID, m Message) error {
+	err := b.local.Publish(ctx, auctionId, m)
+	if notifyErr := b.notify(ctx, auctionId, m); notifyErr != nil {
+		slog.Error("Failed to notify other instances of auction event", "auctionID", auctionId, "kind", m.Kind, "error", notifyErr)
+	}
+	return err
+}
+
+func (b *PostgresBroadcaster) notify(ctx context.Context, auctionId uuid.UUID, m Message) error {
+	payload, err := json.Marshal(auctionEvent{Instance: b.instance, AuctionId: auctionId, UserId: m.UserId, Message: m})
+	if err != nil {
+		return err
+	}
+	return b.queries.NotifyAuctionEvent(ctx, string(payload))
+}
+
+func (b *PostgresBroadcaster) Subscribe(auctionId uuid.UUID) (<-chan Message, func()) {
+	return b.local.Subscribe(auctionId)
+}
+
+// Listen hands the events published by other instances to the local
+// subscribers until ctx is done, reconnecting whenever the listening
+// connection is lost. Each time it starts listening the local rooms catch up
+// from the database on whatever was published while nobody was.
+func (b *PostgresBroadcaster) Listen(ctx context.Context) error {
+	for {
+		err := b.listen(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		slog.Error("Auction events listener stopped, reconnecting", "error", err)
+		select {
+		case <-time.After(listenRetryDelay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (b *PostgresBroadcaster) listen(ctx context.Context) error {
+	conn, err := pgx.ConnectConfig(ctx, b.pool.Config().ConnConfig)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+auctionEventsChannel); err != nil {
+		return err
+	}
+	b.resync(ctx)
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		var event auctionEvent
+		if err := json.Unmarshal([]byte(notification.Payload), &event); err != nil {
+			slog.Error("Failed to decode auction event", "error", err)
+			continue
+		}
+		if event.Instance == b.instance {
+			continue
+		}
+		event.Message.UserId = event.UserId
+		b.local.Publish(ctx, event.AuctionId, event.Message)
+	}
+}
+
+// resync brings every local room in line with the stored deadline and status
+// of its auction.
+func (b *PostgresBroadcaster) resync(ctx context.Context) {
+	for _, auctionId := range b.local.auctions() {
+		m, err := storedAuctionState(ctx, b.queries, auctionId)
+		if err != nil {
+			slog.Error("Failed to resync auction room", "auctionID", auctionId, "error", err)
+			continue
+		}
+		b.local.Publish(ctx, auctionId, m)
+	}
+}
+
+// storedAuctionState describes the stored auction as the event a room would
+// have received last: its result once it is over, otherwise its current end.
+func storedAuctionState(ctx context.Context, q *pgstore.Queries, auctionId uuid.UUID) (Message, error) {
+	product, err := q.GetProductById(ctx, auctionId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Message{Kind: AuctionCancelled, Message: "the auction was removed by the seller"}, nil
+		}
+		return Message{}, err
+	}
+	switch product.Status {
+	case ProductCancelled:
+		return Message{Kind: AuctionCancelled, Message: "the auction was cancelled by the seller"}, nil
+	case ProductEnded:
+		return Message{Kind: AuctionFinished, Message: "auction has been finished without a winner"}, nil
+	case ProductSold, ProductPaid:
+		result, err := q.GetAuctionResultByProductId(ctx, auctionId)
+		if err != nil {
+			return Message{}, err
+		}
+		return Message{
+			Kind:    AuctionFinished,
+			Message: "auction has been finished",
+			Amount:  NewMoney(result.HammerPrice, product.Currency),
+			UserId:  result.WinnerID,
+		}, nil
+	}
+	auctionEnd := product.AuctionEnd
+	return Message{Kind: AuctionRescheduled, Message: "the auction end has changed", AuctionEnd: &auctionEnd}, nil
+}
+
+// LeadSettlement reports whether this instance won the right to settle the
+// auction. The winner must call release once the result is announced.
+func (b *PostgresBroadcaster) LeadSettlement(ctx context.Context, auctionId uuid.UUID) (release func(), led bool, err error) {
+	conn, err := b.pool.Acquire(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	q := pgstore.New(conn)
+	led, err = q.TryLockAuction(ctx, auctionId)
+	if err != nil || !led {
+		conn.Release()
+		return nil, false, err
+	}
+	release = func() {
+		defer conn.Release()
+		if _, err := q.UnlockAuction(context.Background(), auctionId); err != nil {
+			slog.Error("Failed to release settlement lock", "auctionID", auctionId, "error", err)
+		}
+	}
+	return release, true, nil
+}
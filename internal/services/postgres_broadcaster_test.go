@@ -0,0 +1,35 @@
+package services
+
+import (
+	"context"
+	"github.com/FelipePn10/Gobid/internal/store/pgstore"
+	"github.com/google/uuid"
+	"testing"
+	"time"
+)
+
+func TestStoredAuctionState(t *testing.T) {
+	auctionEnd := time.Now().Add(time.Hour).Truncate(time.Second)
+	tests := []struct {
+		name    string
+		product *pgstore.Product
+		want    MessageKind
+	}{
+		{"a live auction reports its end", &pgstore.Product{Status: ProductLive, AuctionEnd: auctionEnd}, AuctionRescheduled},
+		{"a cancelled auction closes the room", &pgstore.Product{Status: ProductCancelled, AuctionEnd: auctionEnd}, AuctionCancelled},
+		{"an ended auction closes the room", &pgstore.Product{Status: ProductEnded, AuctionEnd: auctionEnd}, AuctionFinished},
+		{"a deleted auction closes the room", nil, AuctionCancelled},
+	}
+	for _, tt := range tests {
+		m, err := storedAuctionState(context.Background(), pgstore.New(fakeStore{product: tt.product}), uuid.New())
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if m.Kind != tt.want {
+			t.Errorf("%s: kind = %d, want %d", tt.name, m.Kind, tt.want)
+		}
+		if m.Kind == AuctionRescheduled && !m.AuctionEnd.Equal(auctionEnd) {
+			t.Errorf("%s: auction end = %v, want %v", tt.name, m.AuctionEnd, auctionEnd)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"github.com/FelipePn10/Gobid/internal/store/pgstore"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultProductPageSize = 20
+	MaxProductPageSize     = 100
+)
+
+const (
+	SortEndingSoon = "ending_soon"
+	SortMostBids   = "most_bids"
+	SortNewest     = "newest"
+)
+
+// ProductFilter narrows the catalogue. Zero values leave a field unfiltered,
+// except Status, which defaults to auctions that are still open. Price bounds
+// are minor units of Currency. Category is a slug and also matches its
+// subcategories.
+type ProductFilter struct {
+	Search       string
+	Status       string
+	Category     string
+	Tag          string
+	SellerID     *uuid.UUID
+	Currency     string
+	MinPrice     *int64
+	MaxPrice     *int64
+	EndingBefore *time.Time
+}
+
+// ProductSummary is the public view of a listing. Settings that would help
+// bidders game the auction, such as the reserve price, are left out.
+type ProductSummary struct {
+	ID           uuid.UUID  `json:"id"`
+	SellerID     uuid.UUID  `json:"seller_id"`
+	ProductName  string     `json:"product_name"`
+	Description  string     `json:"description"`
+	AuctionType  string     `json:"auction_type"`
+	Status       string     `json:"status"`
+	BasePrice    Money      `json:"base_price"`
+	CurrentPrice Money      `json:"current_price"`
+	BuyNowPrice  *Money     `json:"buy_now_price,omitempty"`
+	BidCount     int64      `json:"bid_count"`
+	CategoryID   *uuid.UUID `json:"category_id,omitempty"`
+	Tags         []string   `json:"tags"`
+	CoverImage   string     `json:"cover_image,omitempty"`
+	AuctionStart time.Time  `json:"auction_start"`
+	AuctionEnd   time.Time  `json:"auction_end"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+type ProductPage struct {
+	Products   []ProductSummary `json:"products"`
+	NextCursor string           `json:"next_cursor,omitempty"`
+}
+
+// ListProducts returns one page of the catalogue in the given sort order.
+// The cursor is only valid for the sort order that produced it.
+func (ps *ProductsService) ListProducts(ctx context.Context, filter ProductFilter, sort, cursor string, pageSize int32) (ProductPage, error) {
+	if sort == "" {
+		sort = SortEndingSoon
+	}
+	params := pgstore.ListProductsEndingSoonParams{
+		Search:       pgtype.Text{String: filter.Search, Valid: filter.Search != ""},
+		Status:       pgtype.Text{String: filter.Status, Valid: filter.Status != ""},
+		Category:     pgtype.Text{String: filter.Category, Valid: filter.Category != ""},
+		Tag:          pgtype.Text{String: filter.Tag, Valid: filter.Tag != ""},
+		Currency:     pgtype.Text{String: filter.Currency, Valid: filter.Currency != ""},
+		MinPrice:     nullInt64(filter.MinPrice),
+		MaxPrice:     nullInt64(filter.MaxPrice),
+		EndingBefore: nullTime(filter.EndingBefore),
+		PageSize:     pageSize + 1,
+	}
+	if filter.SellerID != nil {
+		params.SellerID = pgtype.UUID{Bytes: *filter.SellerID, Valid: true}
+	}
+	if cursor != "" {
+		key, id, err := decodeProductCursor(cursor, sort)
+		if err != nil {
+			return ProductPage{}, err
+		}
+		params.AfterKey = pgtype.Int8{Int64: key, Valid: true}
+		params.AfterID = pgtype.UUID{Bytes: id, Valid: true}
+	}
+	var rows []pgstore.ProductCatalogue
+	var err error
+	switch sort {
+	case SortNewest:
+		rows, err = ps.queries.ListProductsNewest(ctx, pgstore.ListProductsNewestParams(params))
+	case SortMostBids:
+		rows, err = ps.queries.ListProductsMostBids(ctx, pgstore.ListProductsMostBidsParams(params))
+	default:
+		rows, err = ps.queries.ListProductsEndingSoon(ctx, params)
+	}
+	if err != nil {
+		return ProductPage{}, err
+	}
+
+	page := ProductPage{Products: make([]ProductSummary, 0, len(rows))}
+	if len(rows) > int(pageSize) {
+		rows = rows[:pageSize]
+		last := rows[len(rows)-1]
+		page.NextCursor = encodeProductCursor(sort, productSortKey(last, sort), last.ID)
+	}
+	for _, row := range rows {
+		summary := ProductSummary{
+			ID:           row.ID,
+			SellerID:     row.SellerID,
+			ProductName:  row.ProductName,
+			Description:  row.Description,
+			AuctionType:  row.AuctionType,
+			Status:       row.Status,
+			BasePrice:    Money{Amount: row.Baseprice, Currency: row.Currency},
+			CurrentPrice: Money{Amount: row.CurrentPrice, Currency: row.Currency},
+			BidCount:     row.BidCount,
+			Tags:         row.Tags,
+			CoverImage:   ps.blobURL(row.CoverThumbnailKey),
+			AuctionStart: row.AuctionStart,
+			AuctionEnd:   row.AuctionEnd,
+			CreatedAt:    row.CreatedAt,
+		}
+		if row.CategoryID.Valid {
+			categoryId := uuid.UUID(row.CategoryID.Bytes)
+			summary.CategoryID = &categoryId
+		}
+		if row.BuyNowPrice > 0 {
+			summary.BuyNowPrice = NewMoney(row.BuyNowPrice, row.Currency)
+		}
+		page.Products = append(page.Products, summary)
+	}
+	return page, nil
+}
+
+// productSortKey is the value a catalogue query pages on for sort.
+func productSortKey(product pgstore.ProductCatalogue, sort string) int64 {
+	switch sort {
+	case SortNewest:
+		return product.CreatedAt.UnixMicro()
+	case SortMostBids:
+		return product.BidCount
+	}
+	return product.AuctionEnd.UnixMicro()
+}
+
+func encodeProductCursor(sort string, key int64, id uuid.UUID) string {
+	raw := fmt.Sprintf("%s:%d:%s", sort, key, id)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeProductCursor(cursor, sort string) (int64, uuid.UUID, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, uuid.UUID{}, ErrInvalidCursor
+	}
+	parts := strings.SplitN(string(raw), ":", 3)
+	if len(parts) != 3 || parts[0] != sort {
+		return 0, uuid.UUID{}, ErrInvalidCursor
+	}
+	key, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return 0, uuid.UUID{}, ErrInvalidCursor
+	}
+	id, err := uuid.Parse(parts[2])
+	if err != nil {
+		return 0, uuid.UUID{}, ErrInvalidCursor
+	}
+	return key, id, nil
+}
@@ -0,0 +1,147 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"github.com/FelipePn10/Gobid/internal/store/pgstore"
+	"github.com/google/uuid"
+	"testing"
+	"time"
+)
+
+func TestProductCursorIsBoundToItsSort(t *testing.T) {
+	id := uuid.New()
+	cursor := encodeProductCursor(SortMostBids, -42, id)
+
+	key, gotId, err := decodeProductCursor(cursor, SortMostBids)
+	if err != nil {
+		t.Fatalf("decodeProductCursor: %v", err)
+	}
+	if key != -42 || gotId != id {
+		t.Fatalf("got (%d, %v), want (-42, %v)", key, gotId, id)
+	}
+	if _, _, err := decodeProductCursor(cursor, SortNewest); !errors.Is(err, ErrInvalidCursor) {
+		t.Fatalf("decoding with another sort = %v, want ErrInvalidCursor", err)
+	}
+}
+
+func TestListProductsSearchAndPagination(t *testing.T) {
+	pool := testPool(t)
+	q := pgstore.New(pool)
+	ps := NewProductsService(pool)
+	ctx := context.Background()
+
+	sellerId := createTestUser(t, q)
+	for range 3 {
+		createTestAuction(t, q, sellerId)
+	}
+
+	filter := ProductFilter{Search: "concurrency", SellerID: &sellerId}
+	seen := map[uuid.UUID]bool{}
+	cursor := ""
+	for pages := 0; ; pages++ {
+		if pages > 3 {
+			t.Fatal("pagination did not terminate")
+		}
+		page, err := ps.ListProducts(ctx, filter, SortNewest, cursor, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, product := range page.Products {
+			if seen[product.ID] {
+				t.Fatalf("product %v returned twice", product.ID)
+			}
+			seen[product.ID] = true
+			if product.Status != ProductLive {
+				t.Errorf("status = %q, want %q", product.Status, ProductLive)
+			}
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+	if len(seen) != 3 {
+		t.Fatalf("listed %d products, want 3", len(seen))
+	}
+
+	filter.Search = "no such words anywhere"
+	page, err := ps.ListProducts(ctx, filter, SortNewest, "", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Products) != 0 {
+		t.Fatalf("unmatched search returned %d products", len(page.Products))
+	}
+}
+
+func TestListProductsDefaultsToOpenAuctions(t *testing.T) {
+	pool := testPool(t)
+	q := pgstore.New(pool)
+	ps := NewProductsService(pool)
+	ctx := context.Background()
+
+	sellerId := createTestUser(t, q)
+	openId := createTestAuction(t, q, sellerId)
+	now := time.Now()
+	endedId, err := q.CreatedProduct(ctx, pgstore.CreatedProductParams{
+		SellerID:       sellerId,
+		ProductName:    "finished product",
+		Description:    "a product whose auction finished yesterday",
+		Baseprice:      100_00,
+		AuctionEnd:     now.Add(-24 * time.Hour),
+		IncrementType:  IncrementFixed,
+		IncrementValue: 1_00,
+		AuctionType:    AuctionEnglish,
+		AuctionStart:   now.Add(-48 * time.Hour),
+		Currency:       DefaultCurrency,
+		Status:         ProductLive,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Two full steps have passed since the dutch auction started.
+	dutchId, err := q.CreatedProduct(ctx, pgstore.CreatedProductParams{
+		SellerID:                 sellerId,
+		ProductName:              "dutch product",
+		Description:              "a product whose price drops every minute",
+		Baseprice:                50_00,
+		AuctionEnd:               now.Add(time.Hour),
+		IncrementType:            IncrementFixed,
+		IncrementValue:           1_00,
+		AuctionType:              AuctionDutch,
+		DutchStartPrice:          100_00,
+		DutchFloorPrice:          50_00,
+		DutchPriceStep:           10_00,
+		DutchStepIntervalSeconds: 60,
+		AuctionStart:             now.Add(-150 * time.Second),
+		Currency:                 DefaultCurrency,
+		Status:                   ProductLive,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	page, err := ps.ListProducts(ctx, ProductFilter{SellerID: &sellerId}, "", "", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	listed := map[uuid.UUID]ProductSummary{}
+	for _, product := range page.Products {
+		listed[product.ID] = product
+	}
+	if _, ok := listed[endedId]; ok || len(listed) != 2 {
+		t.Fatalf("default listing = %v, want only the open auctions %v and %v", listed, openId, dutchId)
+	}
+	if got := listed[dutchId].CurrentPrice.Amount; got != 80_00 {
+		t.Errorf("dutch current price = %d, want the scheduled 80_00", got)
+	}
+
+	page, err = ps.ListProducts(ctx, ProductFilter{SellerID: &sellerId, Status: ProductEnded}, "", "", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Products) != 1 || page.Products[0].ID != endedId {
+		t.Fatalf("ended listing = %+v, want only %v", page.Products, endedId)
+	}
+}
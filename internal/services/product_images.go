@@ -0,0 +1,274 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/FelipePn10/Gobid/internal/store/pgstore"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"log/slog"
+	"net/http"
+	"slices"
+	"time"
+)
+
+const (
+	MaxImageBytes    = 10 << 20
+	MaxProductImages = 10
+	maxImagePixels   = 40_000_000
+)
+
+var (
+	ErrImageStorageDisabled = errors.New("image uploads are not configured")
+	ErrUnsupportedImage     = errors.New("the image must be a JPEG, PNG or GIF file")
+	ErrImageTooLarge        = errors.New("the image is too large")
+	ErrTooManyImages        = errors.New("a product cannot have more than 10 images")
+	ErrImageNotFound        = errors.New("image not found")
+	ErrInvalidImageOrder    = errors.New("the new order must list every image of the product exactly once")
+)
+
+// imageFormats maps the sniffed content types that are accepted to the
+// extension of the stored original.
+var imageFormats = map[string]string{
+	"image/jpeg": "jpg",
+	"image/png":  "png",
+	"image/gif":  "gif",
+}
+
+type ProductImage struct {
+	ID           uuid.UUID `json:"id"`
+	Position     int32     `json:"position"`
+	URL          string    `json:"url"`
+	ThumbnailURL string    `json:"thumbnail_url"`
+	Width        int32     `json:"width"`
+	Height       int32     `json:"height"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// AddProductImage stores an uploaded image and its thumbnail and appends it
+// to the product's images. The content type is sniffed from the data rather
+// than trusted from the client.
+func (ps *ProductsService) AddProductImage(ctx context.Context, productId, sellerId uuid.UUID, data []byte) (ProductImage, error) {
+	if ps.Blobs == nil {
+		return ProductImage{}, ErrImageStorageDisabled
+	}
+	if len(data) > MaxImageBytes {
+		return ProductImage{}, ErrImageTooLarge
+	}
+	contentType := http.DetectContentType(data)
+	ext, ok := imageFormats[contentType]
+	if !ok {
+		return ProductImage{}, ErrUnsupportedImage
+	}
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return ProductImage{}, ErrUnsupportedImage
+	}
+	if config.Width*config.Height > maxImagePixels {
+		return ProductImage{}, ErrImageTooLarge
+	}
+	if _, err := ps.sellerProduct(ctx, ps.queries, productId, sellerId); err != nil {
+		return ProductImage{}, err
+	}
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return ProductImage{}, ErrUnsupportedImage
+	}
+
+	var thumb bytes.Buffer
+	thumbContentType, thumbExt := "image/jpeg", "jpg"
+	if contentType == "image/jpeg" {
+		err = jpeg.Encode(&thumb, thumbnail(src, thumbnailSize), &jpeg.Options{Quality: 80})
+	} else {
+		// PNG keeps the transparency GIF and PNG images may have.
+		thumbContentType, thumbExt = "image/png", "png"
+		err = png.Encode(&thumb, thumbnail(src, thumbnailSize))
+	}
+	if err != nil {
+		return ProductImage{}, err
+	}
+
+	imageId := uuid.New()
+	originalKey := fmt.Sprintf("products/%s/%s.%s", productId, imageId, ext)
+	thumbnailKey := fmt.Sprintf("products/%s/%s_thumb.%s", productId, imageId, thumbExt)
+	if err := ps.Blobs.Put(ctx, originalKey, bytes.NewReader(data), contentType); err != nil {
+		return ProductImage{}, err
+	}
+	if err := ps.Blobs.Put(ctx, thumbnailKey, &thumb, thumbContentType); err != nil {
+		ps.deleteBlobs(ctx, originalKey)
+		return ProductImage{}, err
+	}
+
+	stored, err := ps.insertProductImage(ctx, sellerId, pgstore.CreateProductImageParams{
+		ID:           imageId,
+		ProductID:    productId,
+		ContentType:  contentType,
+		OriginalKey:  originalKey,
+		ThumbnailKey: thumbnailKey,
+		Width:        int32(config.Width),
+		Height:       int32(config.Height),
+		SizeBytes:    int64(len(data)),
+	})
+	if err != nil {
+		ps.deleteBlobs(ctx, originalKey, thumbnailKey)
+		return ProductImage{}, err
+	}
+	return ps.productImage(stored), nil
+}
+
+// insertProductImage adds the row while holding the product lock, so
+// concurrent uploads cannot go past the image limit.
+func (ps *ProductsService) insertProductImage(ctx context.Context, sellerId uuid.UUID, params pgstore.CreateProductImageParams) (pgstore.ProductImage, error) {
+	tx, err := ps.pool.Begin(ctx)
+	if err != nil {
+		return pgstore.ProductImage{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := ps.queries.WithTx(tx)
+	if _, err := ps.sellerProduct(ctx, qtx, params.ProductID, sellerId); err != nil {
+		return pgstore.ProductImage{}, err
+	}
+	count, err := qtx.CountProductImages(ctx, params.ProductID)
+	if err != nil {
+		return pgstore.ProductImage{}, err
+	}
+	if count >= MaxProductImages {
+		return pgstore.ProductImage{}, ErrTooManyImages
+	}
+	stored, err := qtx.CreateProductImage(ctx, params)
+	if err != nil {
+		return pgstore.ProductImage{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return pgstore.ProductImage{}, err
+	}
+	return stored, nil
+}
+
+// ListProductImages lists the images of a product in order. Drafts are
+// private to their seller, so anyone else is told they do not exist.
+func (ps *ProductsService) ListProductImages(ctx context.Context, productId, viewerId uuid.UUID) ([]ProductImage, error) {
+	product, err := ps.GetProductByID(ctx, productId)
+	if err != nil {
+		return nil, err
+	}
+	if product.Status == ProductDraft && product.SellerID != viewerId {
+		return nil, ErrProductNotFound
+	}
+	stored, err := ps.queries.ListProductImages(ctx, productId)
+	if err != nil {
+		return nil, err
+	}
+	images := make([]ProductImage, 0, len(stored))
+	for _, image := range stored {
+		images = append(images, ps.productImage(image))
+	}
+	return images, nil
+}
+
+func (ps *ProductsService) DeleteProductImage(ctx context.Context, productId, sellerId, imageId uuid.UUID) error {
+	if _, err := ps.sellerProduct(ctx, ps.queries, productId, sellerId); err != nil {
+		return err
+	}
+	deleted, err := ps.queries.DeleteProductImage(ctx, pgstore.DeleteProductImageParams{ID: imageId, ProductID: productId})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrImageNotFound
+		}
+		return err
+	}
+	ps.deleteBlobs(ctx, deleted.OriginalKey, deleted.ThumbnailKey)
+	return nil
+}
+
+// ReorderProductImages puts the images in the order of imageIds, which must
+// hold every image of the product.
+func (ps *ProductsService) ReorderProductImages(ctx context.Context, productId, sellerId uuid.UUID, imageIds []uuid.UUID) ([]ProductImage, error) {
+	tx, err := ps.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := ps.queries.WithTx(tx)
+	if _, err := ps.sellerProduct(ctx, qtx, productId, sellerId); err != nil {
+		return nil, err
+	}
+	stored, err := qtx.ListProductImages(ctx, productId)
+	if err != nil {
+		return nil, err
+	}
+	current := make([]uuid.UUID, 0, len(stored))
+	for _, image := range stored {
+		current = append(current, image.ID)
+	}
+	requested := slices.Clone(imageIds)
+	slices.SortFunc(current, compareUUIDs)
+	slices.SortFunc(requested, compareUUIDs)
+	if !slices.Equal(current, requested) {
+		return nil, ErrInvalidImageOrder
+	}
+	err = qtx.ReorderProductImages(ctx, pgstore.ReorderProductImagesParams{ProductID: productId, ImageIds: imageIds})
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return ps.ListProductImages(ctx, productId, sellerId)
+}
+
+// sellerProduct locks the product when q runs in a transaction. Products of
+// other sellers are reported as not found.
+func (ps *ProductsService) sellerProduct(ctx context.Context, q *pgstore.Queries, productId, sellerId uuid.UUID) (pgstore.Product, error) {
+	product, err := q.GetProductByIdForUpdate(ctx, productId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return pgstore.Product{}, ErrProductNotFound
+		}
+		return pgstore.Product{}, err
+	}
+	if product.SellerID != sellerId {
+		return pgstore.Product{}, ErrProductNotFound
+	}
+	return product, nil
+}
+
+func (ps *ProductsService) productImage(stored pgstore.ProductImage) ProductImage {
+	return ProductImage{
+		ID:           stored.ID,
+		Position:     stored.Position,
+		URL:          ps.blobURL(stored.OriginalKey),
+		ThumbnailURL: ps.blobURL(stored.ThumbnailKey),
+		Width:        stored.Width,
+		Height:       stored.Height,
+		CreatedAt:    stored.CreatedAt,
+	}
+}
+
+func (ps *ProductsService) blobURL(key string) string {
+	if ps.Blobs == nil || key == "" {
+		return ""
+	}
+	return ps.Blobs.URL(key)
+}
+
+// deleteBlobs is best effort: a leftover blob only wastes space.
+func (ps *ProductsService) deleteBlobs(ctx context.Context, keys ...string) {
+	for _, key := range keys {
+		if err := ps.Blobs.Delete(context.WithoutCancel(ctx), key); err != nil {
+			slog.Error("Failed to delete blob", "key", key, "error", err)
+		}
+	}
+}
+
+func compareUUIDs(a, b uuid.UUID) int {
+	return bytes.Compare(a[:], b[:])
+}
@@ -0,0 +1,36 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"github.com/FelipePn10/Gobid/internal/store/pgstore"
+	"testing"
+	"time"
+)
+
+func TestDraftImagesArePrivateToTheSeller(t *testing.T) {
+	pool := testPool(t)
+	q := pgstore.New(pool)
+	ps := NewProductsService(pool)
+	ctx := context.Background()
+
+	sellerId := createTestUser(t, q)
+	productId, err := ps.CreateProduct(ctx, sellerId, "draft product", "a draft product whose images are private",
+		100_00, time.Now().Add(3*time.Hour), AuctionSettings{
+			Type:      AuctionEnglish,
+			Currency:  DefaultCurrency,
+			Start:     time.Now(),
+			Increment: DefaultIncrementPolicy(DefaultCurrency),
+			Draft:     true,
+		}, ProductClassification{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ps.ListProductImages(ctx, productId, sellerId); err != nil {
+		t.Fatalf("the seller listing their draft's images = %v, want nil", err)
+	}
+	if _, err := ps.ListProductImages(ctx, productId, createTestUser(t, q)); !errors.Is(err, ErrProductNotFound) {
+		t.Fatalf("another user listing a draft's images = %v, want ErrProductNotFound", err)
+	}
+}
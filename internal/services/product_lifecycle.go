@@ -0,0 +1,171 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/FelipePn10/Gobid/internal/store/pgstore"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"slices"
+	"time"
+)
+
+const (
+	ProductDraft     = "draft"
+	ProductScheduled = "scheduled"
+	ProductLive      = "live"
+	ProductEnded     = "ended"
+	ProductSold      = "sold"
+	ProductCancelled = "cancelled"
+	ProductPaid      = "paid"
+)
+
+// productTransitions lists the statuses each status can move to. Ended means
+// the auction closed without a sale.
+var productTransitions = map[string][]string{
+	ProductDraft:     {ProductScheduled, ProductLive, ProductCancelled},
+	ProductScheduled: {ProductLive, ProductCancelled},
+	ProductLive:      {ProductEnded, ProductSold, ProductCancelled},
+	ProductSold:      {ProductPaid},
+}
+
+var (
+	ErrInvalidStatusTransition = errors.New("the product cannot change to this status")
+	ErrAuctionHasBids          = errors.New("the auction already has bids")
+	ErrProductNotEditable      = errors.New("the product can no longer be changed")
+	ErrAuctionCancelled        = errors.New("the auction was cancelled")
+	ErrAuctionEndMovedEarlier  = errors.New("a live auction can only be made to end later")
+)
+
+// LockedFieldError reports a change to a field that the auction's progress
+// no longer allows.
+type LockedFieldError struct {
+	Field string
+	Err   error
+}
+
+func (e *LockedFieldError) Error() string {
+	return fmt.Sprintf("%s cannot be changed: %s", e.Field, e.Err)
+}
+
+func (e *LockedFieldError) Unwrap() error {
+	return e.Err
+}
+
+type InvalidStatusTransitionError struct {
+	From, To string
+}
+
+func (e *InvalidStatusTransitionError) Error() string {
+	return fmt.Sprintf("%s: %s to %s", ErrInvalidStatusTransition, e.From, e.To)
+}
+
+func (e *InvalidStatusTransitionError) Is(target error) bool {
+	return target == ErrInvalidStatusTransition
+}
+
+func CanTransition(from, to string) bool {
+	return slices.Contains(productTransitions[from], to)
+}
+
+// EffectiveStatus is the status of product at now. The stored status lags
+// behind the clock: a scheduled auction goes live at its start and a live
+// one has ended at its end, before anything records it.
+func EffectiveStatus(product pgstore.Product, now time.Time) string {
+	switch product.Status {
+	case ProductScheduled, ProductLive:
+		if !product.AuctionEnd.After(now) {
+			return ProductEnded
+		}
+		if !product.AuctionStart.After(now) {
+			return ProductLive
+		}
+	}
+	return product.Status
+}
+
+// checkLiveUpdate enforces what may change while an auction is live. The
+// name, description, category and tags can always be corrected. The terms
+// bidders rely on are fixed by the first bid, and before that the auction
+// may only be made to end later, since watchers plan around its end.
+func checkLiveUpdate(product pgstore.Product, bids int64, basePrice *int64, auctionEnd *time.Time, reservePrice, buyNowPrice *int64) error {
+	if bids > 0 {
+		terms := []struct {
+			field   string
+			changed bool
+		}{
+			{"base_price", basePrice != nil && *basePrice != product.Baseprice},
+			{"auction_end", auctionEnd != nil && !auctionEnd.Equal(product.AuctionEnd)},
+			{"reserve_price", reservePrice != nil && *reservePrice != product.ReservePrice},
+			{"buy_now_price", buyNowPrice != nil && *buyNowPrice != product.BuyNowPrice},
+		}
+		for _, term := range terms {
+			if term.changed {
+				return &LockedFieldError{Field: term.field, Err: ErrAuctionHasBids}
+			}
+		}
+	}
+	if auctionEnd != nil && auctionEnd.Before(product.AuctionEnd) {
+		return &LockedFieldError{Field: "auction_end", Err: ErrAuctionEndMovedEarlier}
+	}
+	return nil
+}
+
+// initialStatus is the status a new listing is created in.
+func initialStatus(draft bool, start, now time.Time) string {
+	switch {
+	case draft:
+		return ProductDraft
+	case start.After(now):
+		return ProductScheduled
+	default:
+		return ProductLive
+	}
+}
+
+// transitionProduct moves a locked product to status to and records who did
+// it. A nil actor is the system. at is when the change took effect.
+func transitionProduct(ctx context.Context, qtx *pgstore.Queries, product *pgstore.Product, to string, actorId *uuid.UUID, at time.Time) error {
+	if !CanTransition(product.Status, to) {
+		return &InvalidStatusTransitionError{From: product.Status, To: to}
+	}
+	updated, err := qtx.SetProductStatus(ctx, pgstore.SetProductStatusParams{
+		ID:         product.ID,
+		FromStatus: product.Status,
+		ToStatus:   to,
+	})
+	if err != nil {
+		return err
+	}
+	if updated == 0 {
+		return fmt.Errorf("product %s is no longer %s", product.ID, product.Status)
+	}
+	err = qtx.CreateProductStatusTransition(ctx, pgstore.CreateProductStatusTransitionParams{
+		ProductID:  product.ID,
+		FromStatus: pgtype.Text{String: product.Status, Valid: true},
+		ToStatus:   to,
+		ActorID:    nullUUID(actorId),
+		CreatedAt:  at,
+	})
+	if err != nil {
+		return err
+	}
+	if product.Status == ProductDraft && (to == ProductScheduled || to == ProductLive) {
+		if err := notifySubscribers(ctx, qtx, product.ID, product.SellerID); err != nil {
+			return err
+		}
+	}
+	product.Status = to
+	return nil
+}
+
+// advanceProductStatus records that a scheduled auction went live once its
+// start has passed. Closing is left to settlement, which knows whether the
+// product sold.
+func advanceProductStatus(ctx context.Context, qtx *pgstore.Queries, product *pgstore.Product, now time.Time) error {
+	if product.Status != ProductScheduled || product.AuctionStart.After(now) {
+		return nil
+	}
+	return transitionProduct(ctx, qtx, product, ProductLive, nil, product.AuctionStart)
+}
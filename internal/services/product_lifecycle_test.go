@@ -0,0 +1,179 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"github.com/FelipePn10/Gobid/internal/store/pgstore"
+	"testing"
+	"time"
+)
+
+func TestCanTransition(t *testing.T) {
+	tests := []struct {
+		from, to string
+		want     bool
+	}{
+		{ProductDraft, ProductScheduled, true},
+		{ProductScheduled, ProductLive, true},
+		{ProductLive, ProductSold, true},
+		{ProductLive, ProductEnded, true},
+		{ProductSold, ProductPaid, true},
+		{ProductLive, ProductDraft, false},
+		{ProductEnded, ProductSold, false},
+		{ProductSold, ProductCancelled, false},
+		{ProductCancelled, ProductLive, false},
+		{ProductPaid, ProductSold, false},
+	}
+	for _, tt := range tests {
+		if got := CanTransition(tt.from, tt.to); got != tt.want {
+			t.Errorf("CanTransition(%q, %q) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestEffectiveStatus(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		status     string
+		start, end time.Time
+		want       string
+	}{
+		{ProductScheduled, now.Add(time.Hour), now.Add(2 * time.Hour), ProductScheduled},
+		{ProductScheduled, now.Add(-time.Hour), now.Add(time.Hour), ProductLive},
+		{ProductScheduled, now.Add(-2 * time.Hour), now.Add(-time.Hour), ProductEnded},
+		{ProductLive, now.Add(-2 * time.Hour), now.Add(-time.Hour), ProductEnded},
+		{ProductDraft, now.Add(-2 * time.Hour), now.Add(-time.Hour), ProductDraft},
+		{ProductSold, now.Add(-time.Hour), now.Add(time.Hour), ProductSold},
+	}
+	for _, tt := range tests {
+		product := pgstore.Product{Status: tt.status, AuctionStart: tt.start, AuctionEnd: tt.end}
+		if got := EffectiveStatus(product, now); got != tt.want {
+			t.Errorf("EffectiveStatus(%s from %s to %s) = %q, want %q",
+				tt.status, tt.start.Format(time.Kitchen), tt.end.Format(time.Kitchen), got, tt.want)
+		}
+	}
+}
+
+func TestCheckLiveUpdate(t *testing.T) {
+	now := time.Now()
+	product := pgstore.Product{Baseprice: 100_00, ReservePrice: 200_00, AuctionEnd: now.Add(time.Hour)}
+	later, earlier := now.Add(2*time.Hour), now.Add(30*time.Minute)
+	lower := int64(50_00)
+
+	if err := checkLiveUpdate(product, 0, &lower, &later, nil, nil); err != nil {
+		t.Errorf("changing the terms before the first bid = %v, want nil", err)
+	}
+	if err := checkLiveUpdate(product, 0, nil, &earlier, nil, nil); !errors.Is(err, ErrAuctionEndMovedEarlier) {
+		t.Errorf("ending a live auction earlier = %v, want ErrAuctionEndMovedEarlier", err)
+	}
+	var lockedErr *LockedFieldError
+	err := checkLiveUpdate(product, 1, nil, nil, &lower, nil)
+	if !errors.As(err, &lockedErr) || lockedErr.Field != "reserve_price" || !errors.Is(err, ErrAuctionHasBids) {
+		t.Errorf("lowering the reserve after a bid = %v, want reserve_price locked by ErrAuctionHasBids", err)
+	}
+	same := product.Baseprice
+	if err := checkLiveUpdate(product, 1, &same, nil, nil, nil); err != nil {
+		t.Errorf("resending the current base price after a bid = %v, want nil", err)
+	}
+}
+
+func TestCheckPrices(t *testing.T) {
+	tests := []struct {
+		product pgstore.Product
+		want    error
+	}{
+		{pgstore.Product{AuctionType: AuctionEnglish, Baseprice: 100_00}, nil},
+		{pgstore.Product{AuctionType: AuctionEnglish, Baseprice: 100_00, ReservePrice: 100_00, BuyNowPrice: 100_01}, nil},
+		{pgstore.Product{AuctionType: AuctionEnglish, Baseprice: 100_00, ReservePrice: 99_99}, ErrReserveBelowBase},
+		{pgstore.Product{AuctionType: AuctionEnglish, Baseprice: 100_00, BuyNowPrice: 100_00}, ErrBuyNowBelowPrices},
+		{pgstore.Product{AuctionType: AuctionEnglish, Baseprice: 100_00, ReservePrice: 200_00, BuyNowPrice: 150_00}, ErrBuyNowBelowPrices},
+		{pgstore.Product{AuctionType: AuctionVickrey, Baseprice: 100_00, BuyNowPrice: 200_00}, ErrBuyNowNotEnglish},
+		{pgstore.Product{AuctionType: AuctionDutch, Baseprice: 100_00, ReservePrice: 120_00, DutchFloorPrice: 120_00}, nil},
+		{pgstore.Product{AuctionType: AuctionDutch, Baseprice: 100_00, ReservePrice: 150_00, DutchFloorPrice: 120_00}, ErrReserveAboveFloor},
+	}
+	for _, tt := range tests {
+		err := checkPrices(tt.product)
+		if tt.want == nil && err != nil || !errors.Is(err, tt.want) {
+			t.Errorf("checkPrices(base %d, reserve %d, buy now %d, %s) = %v, want %v",
+				tt.product.Baseprice, tt.product.ReservePrice, tt.product.BuyNowPrice, tt.product.AuctionType, err, tt.want)
+		}
+	}
+}
+
+func TestLiveAuctionWithBidsCanOnlyBeCancelled(t *testing.T) {
+	pool := testPool(t)
+	q := pgstore.New(pool)
+	ps := NewProductsService(pool)
+	bs := NewBidsService(pool)
+	ctx := context.Background()
+
+	sellerId := createTestUser(t, q)
+	productId := createTestAuction(t, q, sellerId)
+	if _, err := bs.Placebid(ctx, productId, createTestUser(t, q), Money{Amount: 150_00}); err != nil {
+		t.Fatal(err)
+	}
+
+	name := "renamed"
+	if err := ps.UpdateProduct(ctx, productId, sellerId, &name, nil, nil, nil, nil, nil, ProductClassification{}); err != nil {
+		t.Fatalf("renaming a live auction with bids = %v, want nil", err)
+	}
+	lower := Money{Amount: 50_00}
+	err := ps.UpdateProduct(ctx, productId, sellerId, nil, nil, &lower, nil, nil, nil, ProductClassification{})
+	if !errors.Is(err, ErrAuctionHasBids) {
+		t.Fatalf("lowering the base price of a live auction with bids = %v, want ErrAuctionHasBids", err)
+	}
+	if err := ps.DeleteProduct(ctx, productId, sellerId); !errors.Is(err, ErrAuctionHasBids) {
+		t.Fatalf("deleting a live auction with bids = %v, want ErrAuctionHasBids", err)
+	}
+	if _, err := ps.MarkProductPaid(ctx, productId, sellerId); !errors.Is(err, ErrInvalidStatusTransition) {
+		t.Fatalf("marking a live auction as paid = %v, want ErrInvalidStatusTransition", err)
+	}
+
+	product, err := ps.CancelProduct(ctx, productId, sellerId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if product.Status != ProductCancelled {
+		t.Fatalf("status after cancelling = %q, want %q", product.Status, ProductCancelled)
+	}
+	if _, err := bs.Placebid(ctx, productId, createTestUser(t, q), Money{Amount: 200_00}); !errors.Is(err, ErrAuctionHasEnded) {
+		t.Fatalf("bidding on a cancelled auction = %v, want ErrAuctionHasEnded", err)
+	}
+	if _, err := bs.SettleAuction(ctx, productId); !errors.Is(err, ErrAuctionCancelled) {
+		t.Fatalf("settling a cancelled auction = %v, want ErrAuctionCancelled", err)
+	}
+	err = ps.UpdateProduct(ctx, productId, sellerId, &name, nil, nil, nil, nil, nil, ProductClassification{})
+	if !errors.Is(err, ErrProductNotEditable) {
+		t.Fatalf("updating a cancelled auction = %v, want ErrProductNotEditable", err)
+	}
+}
+
+func TestUpdateScheduledAuctionKeepsMinimumDuration(t *testing.T) {
+	pool := testPool(t)
+	q := pgstore.New(pool)
+	ps := NewProductsService(pool)
+	ctx := context.Background()
+
+	sellerId := createTestUser(t, q)
+	start := time.Now().Add(24 * time.Hour)
+	productId, err := ps.CreateProduct(ctx, sellerId, "scheduled product", "a product whose auction starts tomorrow",
+		100_00, start.Add(3*time.Hour), AuctionSettings{
+			Type:      AuctionEnglish,
+			Currency:  DefaultCurrency,
+			Start:     start,
+			Increment: IncrementPolicy{Type: IncrementFixed, Value: 1_00},
+		}, ProductClassification{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tooSoon := start.Add(time.Hour)
+	err = ps.UpdateProduct(ctx, productId, sellerId, nil, nil, nil, &tooSoon, nil, nil, ProductClassification{})
+	if !errors.Is(err, ErrAuctionTooShort) {
+		t.Fatalf("ending a scheduled auction an hour after it starts = %v, want ErrAuctionTooShort", err)
+	}
+	earlier := start.Add(MinAuctionDuration)
+	if err := ps.UpdateProduct(ctx, productId, sellerId, nil, nil, nil, &earlier, nil, nil, ProductClassification{}); err != nil {
+		t.Fatalf("ending a scheduled auction at the minimum duration = %v, want nil", err)
+	}
+}
@@ -2,11 +2,12 @@ package services
 
 import (
 	"context"
-	"database/sql"
 	"errors"
+	"fmt"
 	"github.com/FelipePn10/Gobid/internal/store/pgstore"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"time"
 )
@@ -14,9 +15,69 @@ import (
 type ProductsService struct {
 	pool    *pgxpool.Pool
 	queries *pgstore.Queries
+
+	// Blobs stores product images. Image uploads are refused when it is nil.
+	Blobs BlobStore
+}
+
+var (
+	ErrProductNotFound   = errors.New("product not found")
+	ErrWrongAuctionType  = errors.New("this action is not available for this auction type")
+	ErrReserveBelowBase  = errors.New("the reserve price cannot be lower than the base price")
+	ErrBuyNowBelowPrices = errors.New("the buy now price must be greater than the base price and the reserve price")
+	ErrBuyNowNotEnglish  = errors.New("only english auctions can have a buy now price")
+	ErrReserveAboveFloor = errors.New("a dutch auction cannot have a reserve price above its floor price")
+	ErrAuctionTooShort   = errors.New("the duration must be at least two hours")
+)
+
+// MinAuctionDuration is the shortest time an auction may run for.
+const MinAuctionDuration = 2 * time.Hour
+
+// ProductFieldError reports a field whose new value the product cannot take.
+type ProductFieldError struct {
+	Field string
+	Err   error
+}
+
+func (e *ProductFieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Err)
 }
 
-var ErrProductNotFound = errors.New("product not found")
+func (e *ProductFieldError) Unwrap() error {
+	return e.Err
+}
+
+const (
+	AuctionEnglish = "english"
+	AuctionDutch   = "dutch"
+	AuctionSealed  = "sealed"
+	AuctionVickrey = "vickrey"
+)
+
+// Prices in AuctionSettings are minor units of Currency.
+type AuctionSettings struct {
+	Type               string
+	Currency           string
+	Start              time.Time
+	SoftCloseWindow    time.Duration
+	SoftCloseExtension time.Duration
+	Increment          IncrementPolicy
+	ReservePrice       int64
+	BuyNowPrice        int64
+	Dutch              DutchSchedule
+
+	// Draft keeps the listing hidden and closed to bids until it is
+	// published.
+	Draft bool
+}
+
+// ProductClassification places a product in the catalogue. Tags must already
+// be normalized. On update a nil CategoryID or Tags leaves the current value
+// unchanged, while an empty Tags removes every tag.
+type ProductClassification struct {
+	CategoryID *uuid.UUID
+	Tags       []string
+}
 
 func NewProductsService(pool *pgxpool.Pool) ProductsService {
 	return ProductsService{
@@ -30,19 +91,65 @@ func (ps *ProductsService) CreateProduct(
 	sellerId uuid.UUID,
 	productName,
 	description string,
-	baseprice float64,
+	baseprice int64,
 	auctionEnd time.Time,
+	settings AuctionSettings,
+	classification ProductClassification,
 ) (uuid.UUID, error) {
-	id, err := ps.queries.CreatedProduct(ctx, pgstore.CreatedProductParams{
-		SellerID:    sellerId,
-		ProductName: productName,
-		Description: description,
-		Baseprice:   baseprice,
-		AuctionEnd:  auctionEnd,
+	tx, err := ps.pool.Begin(ctx)
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := ps.queries.WithTx(tx)
+	now := time.Now()
+	status := initialStatus(settings.Draft, settings.Start, now)
+	id, err := qtx.CreatedProduct(ctx, pgstore.CreatedProductParams{
+		SellerID:                  sellerId,
+		ProductName:               productName,
+		Description:               description,
+		Baseprice:                 baseprice,
+		AuctionEnd:                auctionEnd,
+		SoftCloseWindowMinutes:    int32(settings.SoftCloseWindow / time.Minute),
+		SoftCloseExtensionMinutes: int32(settings.SoftCloseExtension / time.Minute),
+		IncrementType:             settings.Increment.Type,
+		IncrementValue:            settings.Increment.Value,
+		ReservePrice:              settings.ReservePrice,
+		BuyNowPrice:               settings.BuyNowPrice,
+		AuctionType:               settings.Type,
+		DutchStartPrice:           settings.Dutch.StartPrice,
+		DutchFloorPrice:           settings.Dutch.FloorPrice,
+		DutchPriceStep:            settings.Dutch.Step,
+		DutchStepIntervalSeconds:  int32(settings.Dutch.Interval / time.Second),
+		AuctionStart:              settings.Start,
+		Currency:                  settings.Currency,
+		CategoryID:                nullUUID(classification.CategoryID),
+		Status:                    status,
+	})
+	if err != nil {
+		return uuid.UUID{}, categoryError(err)
+	}
+	err = qtx.CreateProductStatusTransition(ctx, pgstore.CreateProductStatusTransitionParams{
+		ProductID: id,
+		ToStatus:  status,
+		ActorID:   nullUUID(&sellerId),
+		CreatedAt: now,
 	})
 	if err != nil {
 		return uuid.UUID{}, err
 	}
+	if err := setProductTags(ctx, qtx, id, classification.Tags); err != nil {
+		return uuid.UUID{}, err
+	}
+	if status != ProductDraft {
+		if err := notifySubscribers(ctx, qtx, id, sellerId); err != nil {
+			return uuid.UUID{}, err
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return uuid.UUID{}, err
+	}
 	return id, nil
 }
 
@@ -52,43 +159,144 @@ func (ps *ProductsService) UpdateProduct(
 	sellerID uuid.UUID,
 	productName *string,
 	description *string,
-	basePrice *float64,
+	basePriceAmount *Money,
 	auctionEnd *time.Time,
+	reservePriceAmount *Money,
+	buyNowPriceAmount *Money,
+	classification ProductClassification,
 ) error {
-	params := pgstore.UpdateProductParams{
-		ID:          productID,
-		SellerID:    sellerID,
-		ProductName: nullString(productName),
-		Description: nullString(description),
-		Baseprice:   nullFloat64(basePrice),
-		AuctionEnd:  nullTime(auctionEnd),
-	}
-	if err := ps.queries.UpdateProduct(ctx, params); err != nil {
+	tx, err := ps.pool.Begin(ctx)
+	if err != nil {
 		return err
 	}
+	defer tx.Rollback(ctx)
+
+	qtx := ps.queries.WithTx(tx)
+	product, bids, err := ps.changeableProduct(ctx, qtx, productID, sellerID)
+	if err != nil {
+		return err
+	}
+	basePrice, err := priceIn(product, "base_price", basePriceAmount)
+	if err != nil {
+		return err
+	}
+	reservePrice, err := priceIn(product, "reserve_price", reservePriceAmount)
+	if err != nil {
+		return err
+	}
+	buyNowPrice, err := priceIn(product, "buy_now_price", buyNowPriceAmount)
+	if err != nil {
+		return err
+	}
+	// A scheduled auction runs from its stored start, not from now.
+	now := time.Now()
+	start := now
+	if product.AuctionStart.After(now) {
+		start = product.AuctionStart
+	}
+	if auctionEnd != nil && auctionEnd.Sub(start) < MinAuctionDuration {
+		return &ProductFieldError{Field: "auction_end", Err: ErrAuctionTooShort}
+	}
+	if EffectiveStatus(product, now) == ProductLive {
+		if err := checkLiveUpdate(product, bids, basePrice, auctionEnd, reservePrice, buyNowPrice); err != nil {
+			return err
+		}
+	}
+	merged := product
+	for _, change := range []struct {
+		price *int64
+		field *int64
+	}{
+		{basePrice, &merged.Baseprice},
+		{reservePrice, &merged.ReservePrice},
+		{buyNowPrice, &merged.BuyNowPrice},
+	} {
+		if change.price != nil {
+			*change.field = *change.price
+		}
+	}
+	if err := checkPrices(merged); err != nil {
+		return err
+	}
+	params := pgstore.UpdateProductParams{
+		ID:           productID,
+		SellerID:     sellerID,
+		ProductName:  nullString(productName),
+		Description:  nullString(description),
+		Baseprice:    nullInt64(basePrice),
+		AuctionEnd:   nullTime(auctionEnd),
+		ReservePrice: nullInt64(reservePrice),
+		BuyNowPrice:  nullInt64(buyNowPrice),
+		CategoryID:   nullUUID(classification.CategoryID),
+	}
+	updated, err := qtx.UpdateProduct(ctx, params)
+	if err != nil {
+		return categoryError(err)
+	}
+	if updated == 0 {
+		return ErrProductNotFound
+	}
+	if classification.Tags != nil {
+		if err := setProductTags(ctx, qtx, productID, classification.Tags); err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+// priceIn returns price in minor units of the product's currency.
+func priceIn(product pgstore.Product, field string, price *Money) (*int64, error) {
+	if price == nil {
+		return nil, nil
+	}
+	resolved, err := price.In(product.Currency)
+	if err != nil {
+		return nil, &ProductFieldError{Field: field, Err: err}
+	}
+	return &resolved.Amount, nil
+}
 
+// checkPrices enforces how the prices of a product relate to each other, as
+// creating one does.
+func checkPrices(product pgstore.Product) error {
+	if product.ReservePrice > 0 && product.ReservePrice < product.Baseprice {
+		return &ProductFieldError{Field: "reserve_price", Err: ErrReserveBelowBase}
+	}
+	// A dutch auction sells at whatever the schedule has dropped to, so a
+	// reserve the floor can go under would be sold through.
+	if product.AuctionType == AuctionDutch && product.ReservePrice > product.DutchFloorPrice {
+		return &ProductFieldError{Field: "reserve_price", Err: ErrReserveAboveFloor}
+	}
+	if product.BuyNowPrice > 0 {
+		if product.AuctionType != AuctionEnglish {
+			return &ProductFieldError{Field: "buy_now_price", Err: ErrBuyNowNotEnglish}
+		}
+		if product.BuyNowPrice <= max(product.Baseprice, product.ReservePrice) {
+			return &ProductFieldError{Field: "buy_now_price", Err: ErrBuyNowBelowPrices}
+		}
+	}
 	return nil
 }
 
-func nullString(s *string) sql.NullString {
+func nullString(s *string) pgtype.Text {
 	if s == nil {
-		return sql.NullString{Valid: false}
+		return pgtype.Text{Valid: false}
 	}
-	return sql.NullString{String: *s, Valid: true}
+	return pgtype.Text{String: *s, Valid: true}
 }
 
-func nullFloat64(f *float64) sql.NullFloat64 {
-	if f == nil {
-		return sql.NullFloat64{Valid: false}
+func nullInt64(i *int64) pgtype.Int8 {
+	if i == nil {
+		return pgtype.Int8{Valid: false}
 	}
-	return sql.NullFloat64{Float64: *f, Valid: true}
+	return pgtype.Int8{Int64: *i, Valid: true}
 }
 
-func nullTime(t *time.Time) sql.NullTime {
+func nullTime(t *time.Time) pgtype.Timestamptz {
 	if t == nil {
-		return sql.NullTime{Valid: false}
+		return pgtype.Timestamptz{Valid: false}
 	}
-	return sql.NullTime{Time: *t, Valid: true}
+	return pgtype.Timestamptz{Time: *t, Valid: true}
 }
 
 func (ps *ProductsService) DeleteProduct(
@@ -96,16 +304,122 @@ func (ps *ProductsService) DeleteProduct(
 	productID uuid.UUID,
 	sellerID uuid.UUID,
 ) error {
-	err := ps.queries.DeleteProduct(ctx, pgstore.DeleteProductParams{
+	tx, err := ps.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := ps.queries.WithTx(tx)
+	_, bids, err := ps.changeableProduct(ctx, qtx, productID, sellerID)
+	if err != nil {
+		return err
+	}
+	// Bids must stay on record, so the seller has to cancel instead.
+	if bids > 0 {
+		return ErrAuctionHasBids
+	}
+	images, err := qtx.ListProductImages(ctx, productID)
+	if err != nil {
+		return err
+	}
+	err = qtx.DeleteProduct(ctx, pgstore.DeleteProductParams{
 		ID:       productID,
 		SellerID: sellerID,
 	})
 	if err != nil {
 		return err
 	}
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+	if ps.Blobs != nil {
+		for _, image := range images {
+			ps.deleteBlobs(ctx, image.OriginalKey, image.ThumbnailKey)
+		}
+	}
 	return nil
 }
 
+// PublishProduct opens a draft listing, which is scheduled or goes live
+// depending on its start.
+func (ps *ProductsService) PublishProduct(ctx context.Context, productID, sellerID uuid.UUID) (pgstore.Product, error) {
+	return ps.transitionSellerProduct(ctx, productID, sellerID, func(product pgstore.Product, now time.Time) string {
+		return initialStatus(false, product.AuctionStart, now)
+	})
+}
+
+// CancelProduct withdraws a listing that has not closed yet. Unlike deletion
+// it is allowed once bids exist, which stay on record.
+func (ps *ProductsService) CancelProduct(ctx context.Context, productID, sellerID uuid.UUID) (pgstore.Product, error) {
+	return ps.transitionSellerProduct(ctx, productID, sellerID, func(pgstore.Product, time.Time) string {
+		return ProductCancelled
+	})
+}
+
+// MarkProductPaid records that the seller received payment for a sale.
+func (ps *ProductsService) MarkProductPaid(ctx context.Context, productID, sellerID uuid.UUID) (pgstore.Product, error) {
+	return ps.transitionSellerProduct(ctx, productID, sellerID, func(pgstore.Product, time.Time) string {
+		return ProductPaid
+	})
+}
+
+func (ps *ProductsService) transitionSellerProduct(ctx context.Context, productID, sellerID uuid.UUID, next func(pgstore.Product, time.Time) string) (pgstore.Product, error) {
+	tx, err := ps.pool.Begin(ctx)
+	if err != nil {
+		return pgstore.Product{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := ps.queries.WithTx(tx)
+	product, err := ps.sellerProduct(ctx, qtx, productID, sellerID)
+	if err != nil {
+		return pgstore.Product{}, err
+	}
+	now := time.Now()
+	if err := advanceProductStatus(ctx, qtx, &product, now); err != nil {
+		return pgstore.Product{}, err
+	}
+	// A live auction past its end only waits for settlement, and a draft
+	// past its end can no longer run.
+	open := product.Status == ProductLive || product.Status == ProductDraft
+	if open && !product.AuctionEnd.After(now) {
+		return pgstore.Product{}, ErrAuctionHasEnded
+	}
+	if err := transitionProduct(ctx, qtx, &product, next(product, now), &sellerID, now); err != nil {
+		return pgstore.Product{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return pgstore.Product{}, err
+	}
+	return product, nil
+}
+
+// changeableProduct locks a product of the seller that has not closed yet
+// and counts its bids, which only a live auction can have.
+func (ps *ProductsService) changeableProduct(ctx context.Context, qtx *pgstore.Queries, productID, sellerID uuid.UUID) (pgstore.Product, int64, error) {
+	product, err := ps.sellerProduct(ctx, qtx, productID, sellerID)
+	if err != nil {
+		return pgstore.Product{}, 0, err
+	}
+	now := time.Now()
+	if err := advanceProductStatus(ctx, qtx, &product, now); err != nil {
+		return pgstore.Product{}, 0, err
+	}
+	switch EffectiveStatus(product, now) {
+	case ProductDraft, ProductScheduled:
+		return product, 0, nil
+	case ProductLive:
+		bids, err := qtx.CountBidsByProductId(ctx, productID)
+		if err != nil {
+			return pgstore.Product{}, 0, err
+		}
+		return product, bids, nil
+	default:
+		return pgstore.Product{}, 0, ErrProductNotEditable
+	}
+}
+
 func (ps *ProductsService) GetProductByID(ctx context.Context, productID uuid.UUID) (pgstore.Product, error) {
 	product, err := ps.queries.GetProductById(ctx, productID)
 	if err != nil {
@@ -116,3 +430,11 @@ func (ps *ProductsService) GetProductByID(ctx context.Context, productID uuid.UU
 	}
 	return product, nil
 }
+
+func (ps *ProductsService) GetActiveAuctions(ctx context.Context) ([]pgstore.Product, error) {
+	products, err := ps.queries.ListActiveAuctions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return products, nil
+}
@@ -0,0 +1,37 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/google/uuid"
+)
+
+// pseudonymKey keys bidder pseudonyms so that nobody who knows a user's id
+// can work out their pseudonym. Instances must share it for their pseudonyms
+// to match; until SetPseudonymKey is called each process uses its own.
+var pseudonymKey = randomPseudonymKey()
+
+func randomPseudonymKey() []byte {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		panic(err)
+	}
+	return key
+}
+
+// SetPseudonymKey sets the secret bidder pseudonyms are derived from. It must
+// be called before any room starts.
+func SetPseudonymKey(key []byte) {
+	pseudonymKey = key
+}
+
+// BidderPseudonym names a bidder consistently within one auction without
+// revealing who they are or linking them across auctions.
+func BidderPseudonym(auctionId, bidderId uuid.UUID) string {
+	mac := hmac.New(sha256.New, pseudonymKey)
+	mac.Write(auctionId[:])
+	mac.Write(bidderId[:])
+	return "bidder-" + hex.EncodeToString(mac.Sum(nil)[:8])
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"github.com/FelipePn10/Gobid/internal/store/pgstore"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"time"
+)
+
+const snapshotTimeout = 5 * time.Second
+
+// AuctionSnapshot is the state of an auction as a newly connected client
+// needs it. Sealed auctions leave out everything that would reveal the bids,
+// down to how many there are.
+type AuctionSnapshot struct {
+	ProductId        uuid.UUID `json:"productId"`
+	ProductName      string    `json:"productName"`
+	Description      string    `json:"description"`
+	AuctionType      string    `json:"auctionType"`
+	BasePrice        Money     `json:"basePrice"`
+	BuyNowPrice      *Money    `json:"buyNowPrice,omitempty"`
+	CurrentPrice     *Money    `json:"currentPrice,omitempty"`
+	HighBid          *Money    `json:"highBid,omitempty"`
+	HighBidder       string    `json:"highBidder,omitempty"`
+	BidCount         *int64    `json:"bidCount,omitempty"`
+	ReserveMet       *bool     `json:"reserveMet,omitempty"`
+	AuctionStart     time.Time `json:"auctionStart"`
+	AuctionEnd       time.Time `json:"auctionEnd"`
+	RemainingSeconds int64     `json:"remainingSeconds"`
+	Watchers         int       `json:"watchers"`
+	Cursor           string    `json:"cursor"`
+}
+
+type auctionState struct {
+	product  pgstore.Product
+	highest  *pgstore.Bid
+	bidCount int64
+}
+
+func (bs *BidsService) auctionState(ctx context.Context, productId uuid.UUID) (auctionState, error) {
+	product, err := bs.getProduct(ctx, productId)
+	if err != nil {
+		return auctionState{}, err
+	}
+	state := auctionState{product: product}
+	highest, err := bs.queries.GetHighestBidByProductId(ctx, productId)
+	switch {
+	case err == nil:
+		state.highest = &highest
+	case !errors.Is(err, pgx.ErrNoRows):
+		return auctionState{}, err
+	}
+	state.bidCount, err = bs.queries.CountBidsByProductId(ctx, productId)
+	if err != nil {
+		return auctionState{}, err
+	}
+	return state, nil
+}
+
+func (r *AuctionRoom) snapshot() (Message, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), snapshotTimeout)
+	defer cancel()
+	state, err := r.BidsService.auctionState(ctx, r.Id)
+	if err != nil {
+		return Message{}, err
+	}
+
+	product := state.product
+	auctionEnd := product.AuctionEnd
+	if deadline, ok := r.Context.Deadline(); ok && !r.awaitingResult {
+		auctionEnd = deadline
+	}
+	snapshot := AuctionSnapshot{
+		ProductId:        product.ID,
+		ProductName:      product.ProductName,
+		Description:      product.Description,
+		AuctionType:      product.AuctionType,
+		BasePrice:        *r.money(product.Baseprice),
+		AuctionStart:     product.AuctionStart,
+		AuctionEnd:       auctionEnd,
+		RemainingSeconds: max(0, int64(time.Until(auctionEnd).Seconds())),
+		Watchers:         len(r.Clients),
+		Cursor:           r.cursor().String(),
+	}
+	if product.BuyNowPrice > 0 {
+		snapshot.BuyNowPrice = r.money(product.BuyNowPrice)
+	}
+	if r.Dutch != nil {
+		snapshot.CurrentPrice = r.money(r.Dutch.PriceAt(time.Now()))
+	}
+	sealed := product.AuctionType == AuctionSealed || product.AuctionType == AuctionVickrey
+	if !sealed {
+		snapshot.BidCount = &state.bidCount
+		var highBid int64
+		if state.highest != nil {
+			highBid = state.highest.BidAmount
+			snapshot.HighBid = r.money(highBid)
+			snapshot.HighBidder = BidderPseudonym(r.Id, state.highest.BidderID)
+		}
+		snapshot.ReserveMet = reserveMet(product, highBid)
+	}
+	return Message{Kind: RoomSnapshot, Snapshot: &snapshot}, nil
+}
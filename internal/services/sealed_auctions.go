@@ -0,0 +1,54 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"github.com/FelipePn10/Gobid/internal/store/pgstore"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// placeSealedBid records or revises the single bid a bidder may hold in a
+// sealed auction. Amounts are only compared against the base price since the
+// other bids stay hidden until the auction closes.
+func placeSealedBid(ctx context.Context, qtx *pgstore.Queries, product pgstore.Product, bidderId uuid.UUID, amount int64) (PlacedBid, error) {
+	if product.Baseprice >= amount {
+		return PlacedBid{}, ErrBidIsTooLow
+	}
+	bid, err := qtx.GetBidByProductAndBidder(ctx, pgstore.GetBidByProductAndBidderParams{
+		ProductID: product.ID,
+		BidderID:  bidderId,
+	})
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return PlacedBid{}, err
+		}
+		bid, err = qtx.CreateBid(ctx, pgstore.CreateBidParams{
+			ProductID: product.ID,
+			BidderID:  bidderId,
+			BidAmount: amount,
+		})
+	} else {
+		bid, err = qtx.UpdateBidAmount(ctx, pgstore.UpdateBidAmountParams{
+			ID:        bid.ID,
+			BidAmount: amount,
+		})
+	}
+	if err != nil {
+		return PlacedBid{}, err
+	}
+	return PlacedBid{Bid: bid, AuctionEnd: product.AuctionEnd, Sealed: true}, nil
+}
+
+// hammerPrice is what the winner of bids pays: their own bid, or the second
+// highest bid in a vickrey auction, never less than the base or reserve price.
+func hammerPrice(product pgstore.Product, bids []pgstore.Bid) int64 {
+	if product.AuctionType != AuctionVickrey {
+		return bids[0].BidAmount
+	}
+	price := max(product.Baseprice, product.ReservePrice)
+	if len(bids) > 1 {
+		price = max(price, bids[1].BidAmount)
+	}
+	return price
+}
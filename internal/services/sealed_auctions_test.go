@@ -0,0 +1,37 @@
+package services
+
+import (
+	"github.com/FelipePn10/Gobid/internal/store/pgstore"
+	"testing"
+)
+
+func TestHammerPrice(t *testing.T) {
+	bids := func(amounts ...int64) []pgstore.Bid {
+		bids := make([]pgstore.Bid, len(amounts))
+		for i, amount := range amounts {
+			bids[i] = pgstore.Bid{BidAmount: amount}
+		}
+		return bids
+	}
+	tests := []struct {
+		name         string
+		auctionType  string
+		reservePrice int64
+		bids         []pgstore.Bid
+		want         int64
+	}{
+		{"sealed pays the winning bid", AuctionSealed, 0, bids(300_00, 200_00), 300_00},
+		{"vickrey pays the second bid", AuctionVickrey, 0, bids(300_00, 200_00), 200_00},
+		{"vickrey with one bid pays the base price", AuctionVickrey, 0, bids(300_00), 100_00},
+		{"vickrey with one bid pays the reserve", AuctionVickrey, 150_00, bids(300_00), 150_00},
+		{"vickrey with tied top bids pays the tie", AuctionVickrey, 0, bids(250_00, 250_00, 120_00), 250_00},
+		{"vickrey pays a reserve above the second bid", AuctionVickrey, 180_00, bids(300_00, 150_00), 180_00},
+		{"vickrey pays the base price above the second bid", AuctionVickrey, 0, bids(300_00, 90_00), 100_00},
+	}
+	for _, tt := range tests {
+		product := pgstore.Product{AuctionType: tt.auctionType, Baseprice: 100_00, ReservePrice: tt.reservePrice}
+		if got := hammerPrice(product, tt.bids); got != tt.want {
+			t.Errorf("%s: hammerPrice = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,256 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"github.com/FelipePn10/Gobid/internal/store/pgstore"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"strings"
+)
+
+const MaxProductTags = 10
+
+var (
+	ErrCategoryNotFound  = errors.New("category not found")
+	ErrCategorySlugTaken = errors.New("a category with this slug already exists")
+	ErrCategoryCycle     = errors.New("a category cannot be moved under itself or one of its subcategories")
+	ErrCategoryInUse     = errors.New("the category still has subcategories or products")
+	ErrTagNotFound       = errors.New("tag not found")
+	ErrTagExists         = errors.New("a tag with this name already exists")
+)
+
+type TaxonomyService struct {
+	pool    *pgxpool.Pool
+	queries *pgstore.Queries
+}
+
+func NewTaxonomyService(pool *pgxpool.Pool) TaxonomyService {
+	return TaxonomyService{
+		pool:    pool,
+		queries: pgstore.New(pool),
+	}
+}
+
+type CategoryNode struct {
+	ID       uuid.UUID       `json:"id"`
+	ParentID *uuid.UUID      `json:"parent_id,omitempty"`
+	Name     string          `json:"name"`
+	Slug     string          `json:"slug"`
+	Children []*CategoryNode `json:"children,omitempty"`
+}
+
+// NormalizeTag turns free-form input such as " Vintage Cameras" into the
+// stored tag name "vintage-cameras".
+func NormalizeTag(tag string) string {
+	return strings.Join(strings.Fields(strings.ToLower(tag)), "-")
+}
+
+func NormalizeTags(tags []string) []string {
+	if tags == nil {
+		return nil
+	}
+	normalized := make([]string, len(tags))
+	for i, tag := range tags {
+		normalized[i] = NormalizeTag(tag)
+	}
+	return normalized
+}
+
+func (ts *TaxonomyService) CreateCategory(ctx context.Context, parentId *uuid.UUID, name, slug string) (CategoryNode, error) {
+	category, err := ts.queries.CreateCategory(ctx, pgstore.CreateCategoryParams{
+		ParentID: nullUUID(parentId),
+		Name:     name,
+		Slug:     slug,
+	})
+	if err != nil {
+		return CategoryNode{}, categoryError(err)
+	}
+	return categoryNode(category), nil
+}
+
+// UpdateCategory renames a category and, when move is set, puts it under
+// parentId, or at the top of the tree when parentId is nil.
+func (ts *TaxonomyService) UpdateCategory(ctx context.Context, id uuid.UUID, name, slug *string, move bool, parentId *uuid.UUID) (CategoryNode, error) {
+	if move && parentId != nil {
+		cycle, err := ts.queries.IsCategoryInSubtree(ctx, pgstore.IsCategoryInSubtreeParams{
+			RootID:     id,
+			CategoryID: *parentId,
+		})
+		if err != nil {
+			return CategoryNode{}, err
+		}
+		if cycle {
+			return CategoryNode{}, ErrCategoryCycle
+		}
+	}
+	category, err := ts.queries.UpdateCategory(ctx, pgstore.UpdateCategoryParams{
+		ID:       id,
+		Name:     nullString(name),
+		Slug:     nullString(slug),
+		Move:     move,
+		ParentID: nullUUID(parentId),
+	})
+	if err != nil {
+		return CategoryNode{}, categoryError(err)
+	}
+	return categoryNode(category), nil
+}
+
+func (ts *TaxonomyService) DeleteCategory(ctx context.Context, id uuid.UUID) error {
+	deleted, err := ts.queries.DeleteCategory(ctx, id)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return ErrCategoryInUse
+		}
+		return err
+	}
+	if deleted == 0 {
+		return ErrCategoryNotFound
+	}
+	return nil
+}
+
+// CategoryTree returns the top level categories with their subcategories
+// nested under them.
+func (ts *TaxonomyService) CategoryTree(ctx context.Context) ([]*CategoryNode, error) {
+	categories, err := ts.queries.ListCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	nodes := make(map[uuid.UUID]*CategoryNode, len(categories))
+	for _, category := range categories {
+		node := categoryNode(category)
+		nodes[category.ID] = &node
+	}
+	roots := []*CategoryNode{}
+	for _, category := range categories {
+		node := nodes[category.ID]
+		if node.ParentID == nil {
+			roots = append(roots, node)
+			continue
+		}
+		parent := nodes[*node.ParentID]
+		parent.Children = append(parent.Children, node)
+	}
+	return roots, nil
+}
+
+func (ts *TaxonomyService) ListTags(ctx context.Context) ([]pgstore.ListTagsRow, error) {
+	return ts.queries.ListTags(ctx)
+}
+
+func (ts *TaxonomyService) RenameTag(ctx context.Context, id uuid.UUID, name string) (pgstore.Tag, error) {
+	tag, err := ts.queries.RenameTag(ctx, pgstore.RenameTagParams{ID: id, Name: NormalizeTag(name)})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return pgstore.Tag{}, ErrTagNotFound
+		case errors.As(err, &pgErr) && pgErr.Code == "23505":
+			return pgstore.Tag{}, ErrTagExists
+		}
+		return pgstore.Tag{}, err
+	}
+	return tag, nil
+}
+
+func (ts *TaxonomyService) DeleteTag(ctx context.Context, id uuid.UUID) error {
+	deleted, err := ts.queries.DeleteTag(ctx, id)
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return ErrTagNotFound
+	}
+	return nil
+}
+
+// SubscribeToCategory makes the user a notification target for products
+// listed under the category or any of its subcategories.
+func (ts *TaxonomyService) SubscribeToCategory(ctx context.Context, userId, categoryId uuid.UUID) error {
+	err := ts.queries.SubscribeToCategory(ctx, pgstore.SubscribeToCategoryParams{UserID: userId, CategoryID: categoryId})
+	if err != nil {
+		return categoryError(err)
+	}
+	return nil
+}
+
+func (ts *TaxonomyService) UnsubscribeFromCategory(ctx context.Context, userId, categoryId uuid.UUID) error {
+	return ts.queries.UnsubscribeFromCategory(ctx, pgstore.UnsubscribeFromCategoryParams{UserID: userId, CategoryID: categoryId})
+}
+
+func (ts *TaxonomyService) SubscribeToTag(ctx context.Context, userId uuid.UUID, name string) error {
+	tag, err := ts.getTag(ctx, name)
+	if err != nil {
+		return err
+	}
+	return ts.queries.SubscribeToTag(ctx, pgstore.SubscribeToTagParams{UserID: userId, TagID: tag.ID})
+}
+
+func (ts *TaxonomyService) UnsubscribeFromTag(ctx context.Context, userId uuid.UUID, name string) error {
+	tag, err := ts.getTag(ctx, name)
+	if err != nil {
+		return err
+	}
+	return ts.queries.UnsubscribeFromTag(ctx, pgstore.UnsubscribeFromTagParams{UserID: userId, TagID: tag.ID})
+}
+
+func (ts *TaxonomyService) getTag(ctx context.Context, name string) (pgstore.Tag, error) {
+	tag, err := ts.queries.GetTagByName(ctx, NormalizeTag(name))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return pgstore.Tag{}, ErrTagNotFound
+		}
+		return pgstore.Tag{}, err
+	}
+	return tag, nil
+}
+
+// setProductTags replaces the tags of a product, creating the ones that do
+// not exist yet.
+func setProductTags(ctx context.Context, qtx *pgstore.Queries, productId uuid.UUID, tags []string) error {
+	if err := qtx.ClearProductTags(ctx, productId); err != nil {
+		return err
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+	if err := qtx.CreateTags(ctx, tags); err != nil {
+		return err
+	}
+	return qtx.AddProductTags(ctx, pgstore.AddProductTagsParams{ProductID: productId, Names: tags})
+}
+
+func categoryError(err error) error {
+	var pgErr *pgconn.PgError
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return ErrCategoryNotFound
+	case errors.As(err, &pgErr) && pgErr.Code == "23505":
+		return ErrCategorySlugTaken
+	case errors.As(err, &pgErr) && pgErr.Code == "23503":
+		return ErrCategoryNotFound
+	}
+	return err
+}
+
+func categoryNode(category pgstore.Category) CategoryNode {
+	node := CategoryNode{ID: category.ID, Name: category.Name, Slug: category.Slug}
+	if category.ParentID.Valid {
+		parentId := uuid.UUID(category.ParentID.Bytes)
+		node.ParentID = &parentId
+	}
+	return node
+}
+
+func nullUUID(id *uuid.UUID) pgtype.UUID {
+	if id == nil {
+		return pgtype.UUID{Valid: false}
+	}
+	return pgtype.UUID{Bytes: *id, Valid: true}
+}
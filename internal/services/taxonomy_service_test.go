@@ -0,0 +1,93 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"github.com/FelipePn10/Gobid/internal/store/pgstore"
+	"github.com/google/uuid"
+	"testing"
+)
+
+func TestNormalizeTag(t *testing.T) {
+	tests := map[string]string{
+		"vintage":            "vintage",
+		" Vintage  Cameras ": "vintage-cameras",
+		"LEGO":               "lego",
+		"":                   "",
+	}
+	for input, want := range tests {
+		if got := NormalizeTag(input); got != want {
+			t.Errorf("NormalizeTag(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestCategoryTreeRejectsCycles(t *testing.T) {
+	pool := testPool(t)
+	ts := NewTaxonomyService(pool)
+	ctx := context.Background()
+
+	suffix := uuid.NewString()[:8]
+	parent, err := ts.CreateCategory(ctx, nil, "Electronics", "electronics-"+suffix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	child, err := ts.CreateCategory(ctx, &parent.ID, "Cameras", "cameras-"+suffix)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ts.UpdateCategory(ctx, parent.ID, nil, nil, true, &child.ID); !errors.Is(err, ErrCategoryCycle) {
+		t.Fatalf("moving a category under its child = %v, want ErrCategoryCycle", err)
+	}
+	if err := ts.DeleteCategory(ctx, parent.ID); !errors.Is(err, ErrCategoryInUse) {
+		t.Fatalf("deleting a category with children = %v, want ErrCategoryInUse", err)
+	}
+
+	tree, err := ts.CategoryTree(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, root := range tree {
+		if root.ID != parent.ID {
+			continue
+		}
+		if len(root.Children) != 1 || root.Children[0].ID != child.ID {
+			t.Fatalf("children of %q = %+v, want only %q", root.Slug, root.Children, child.Slug)
+		}
+		return
+	}
+	t.Fatalf("category %q is not a root of the tree", parent.Slug)
+}
+
+func TestProductTagsAreReplacedOnUpdate(t *testing.T) {
+	pool := testPool(t)
+	q := pgstore.New(pool)
+	ps := NewProductsService(pool)
+	ctx := context.Background()
+
+	sellerId := createTestUser(t, q)
+	productId := createTestAuction(t, q, sellerId)
+	tag := "tag-" + uuid.NewString()[:8]
+
+	err := ps.UpdateProduct(ctx, productId, sellerId, nil, nil, nil, nil, nil, nil, ProductClassification{Tags: []string{tag, "other"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ps.UpdateProduct(ctx, productId, sellerId, nil, nil, nil, nil, nil, nil, ProductClassification{Tags: []string{tag}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tags, err := q.ListTagNamesByProductId(ctx, productId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tags) != 1 || tags[0] != tag {
+		t.Fatalf("tags = %v, want [%s]", tags, tag)
+	}
+
+	err = ps.UpdateProduct(ctx, productId, createTestUser(t, q), nil, nil, nil, nil, nil, nil, ProductClassification{Tags: []string{}})
+	if !errors.Is(err, ErrProductNotFound) {
+		t.Fatalf("updating another seller's product = %v, want ErrProductNotFound", err)
+	}
+}
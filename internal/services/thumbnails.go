@@ -0,0 +1,56 @@
+package services
+
+import (
+	"image"
+	"image/color"
+)
+
+const thumbnailSize = 320
+
+// maxSamples bounds how many source pixels are averaged into one thumbnail
+// pixel along each axis, which keeps large photos cheap to shrink.
+const maxSamples = 4
+
+// thumbnail shrinks src to fit in a size by size square, keeping its aspect
+// ratio. Each pixel is the average of an evenly spaced grid of the source
+// pixels it covers. Images that already fit are copied unscaled.
+func thumbnail(src image.Image, size int) *image.RGBA {
+	bounds := src.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	tw, th := w, h
+	if w > size || h > size {
+		if w >= h {
+			tw, th = size, max(1, h*size/w)
+		} else {
+			tw, th = max(1, w*size/h), size
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, tw, th))
+	for y := 0; y < th; y++ {
+		y0, y1 := bounds.Min.Y+y*h/th, bounds.Min.Y+(y+1)*h/th
+		for x := 0; x < tw; x++ {
+			x0, x1 := bounds.Min.X+x*w/tw, bounds.Min.X+(x+1)*w/tw
+			dst.SetRGBA(x, y, averageColor(src, x0, y0, max(x1, x0+1), max(y1, y0+1)))
+		}
+	}
+	return dst
+}
+
+func averageColor(src image.Image, x0, y0, x1, y1 int) color.RGBA {
+	stepX, stepY := max(1, (x1-x0)/maxSamples), max(1, (y1-y0)/maxSamples)
+	var r, g, b, a, n uint64
+	for y := y0; y < y1; y += stepY {
+		for x := x0; x < x1; x += stepX {
+			sr, sg, sb, sa := src.At(x, y).RGBA()
+			r, g, b, a = r+uint64(sr), g+uint64(sg), b+uint64(sb), a+uint64(sa)
+			n++
+		}
+	}
+	return color.RGBA{
+		R: uint8(r / n >> 8),
+		G: uint8(g / n >> 8),
+		B: uint8(b / n >> 8),
+		A: uint8(a / n >> 8),
+	}
+}
@@ -0,0 +1,39 @@
+package services
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestThumbnailKeepsAspectRatio(t *testing.T) {
+	tests := []struct {
+		w, h, wantW, wantH int
+	}{
+		{1600, 800, 320, 160},
+		{600, 1200, 160, 320},
+		{200, 100, 200, 100},
+		{5000, 3, 320, 1},
+	}
+	for _, tt := range tests {
+		thumb := thumbnail(image.NewGray(image.Rect(0, 0, tt.w, tt.h)), thumbnailSize)
+		if got := thumb.Bounds().Size(); got.X != tt.wantW || got.Y != tt.wantH {
+			t.Errorf("thumbnail of %dx%d is %dx%d, want %dx%d", tt.w, tt.h, got.X, got.Y, tt.wantW, tt.wantH)
+		}
+	}
+}
+
+func TestThumbnailAveragesPixels(t *testing.T) {
+	// Alternating black and white columns shrink to a uniform grey.
+	src := image.NewGray(image.Rect(0, 0, 640, 640))
+	for y := 0; y < 640; y++ {
+		for x := 0; x < 640; x += 2 {
+			src.SetGray(x, y, color.Gray{Y: 255})
+		}
+	}
+	thumb := thumbnail(src, thumbnailSize)
+	got := thumb.RGBAAt(100, 100)
+	if got.R < 120 || got.R > 135 || got.A != 255 {
+		t.Fatalf("pixel = %+v, want an opaque mid grey", got)
+	}
+}
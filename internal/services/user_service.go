@@ -67,3 +67,14 @@ func (us *UserService) AuthenticatUser(ctx context.Context, email, password stri
 	}
 	return user.ID, nil
 }
+
+func (us *UserService) IsAdmin(ctx context.Context, userId uuid.UUID) (bool, error) {
+	isAdmin, err := us.queries.IsUserAdmin(ctx, userId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+	return isAdmin, nil
+}
@@ -0,0 +1,43 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+// source: auction_events.sql
+
+package pgstore
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const notifyAuctionEvent = `-- name: NotifyAuctionEvent :exec
+SELECT pg_notify('auction_events', $1::text)
+`
+
+func (q *Queries) NotifyAuctionEvent(ctx context.Context, payload string) error {
+	_, err := q.db.Exec(ctx, notifyAuctionEvent, payload)
+	return err
+}
+
+const tryLockAuction = `-- name: TryLockAuction :one
+SELECT pg_try_advisory_lock(hashtextextended($1::uuid::text, 0))
+`
+
+func (q *Queries) TryLockAuction(ctx context.Context, productID uuid.UUID) (bool, error) {
+	row := q.db.QueryRow(ctx, tryLockAuction, productID)
+	var pg_try_advisory_lock bool
+	err := row.Scan(&pg_try_advisory_lock)
+	return pg_try_advisory_lock, err
+}
+
+const unlockAuction = `-- name: UnlockAuction :one
+SELECT pg_advisory_unlock(hashtextextended($1::uuid::text, 0))
+`
+
+func (q *Queries) UnlockAuction(ctx context.Context, productID uuid.UUID) (bool, error) {
+	row := q.db.QueryRow(ctx, unlockAuction, productID)
+	var pg_advisory_unlock bool
+	err := row.Scan(&pg_advisory_unlock)
+	return pg_advisory_unlock, err
+}
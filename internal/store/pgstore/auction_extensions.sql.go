@@ -0,0 +1,69 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+// source: auction_extensions.sql
+
+package pgstore
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const createAuctionExtension = `-- name: CreateAuctionExtension :exec
+INSERT INTO auction_extensions (
+    product_id, bid_id, previous_end, new_end
+) VALUES ($1, $2, $3, $4)
+`
+
+type CreateAuctionExtensionParams struct {
+	ProductID   uuid.UUID `json:"product_id"`
+	BidID       uuid.UUID `json:"bid_id"`
+	PreviousEnd time.Time `json:"previous_end"`
+	NewEnd      time.Time `json:"new_end"`
+}
+
+func (q *Queries) CreateAuctionExtension(ctx context.Context, arg CreateAuctionExtensionParams) error {
+	_, err := q.db.Exec(ctx, createAuctionExtension,
+		arg.ProductID,
+		arg.BidID,
+		arg.PreviousEnd,
+		arg.NewEnd,
+	)
+	return err
+}
+
+const listAuctionExtensionsByProductId = `-- name: ListAuctionExtensionsByProductId :many
+SELECT id, product_id, bid_id, previous_end, new_end, created_at FROM auction_extensions
+WHERE product_id = $1
+ORDER BY created_at
+`
+
+func (q *Queries) ListAuctionExtensionsByProductId(ctx context.Context, productID uuid.UUID) ([]AuctionExtension, error) {
+	rows, err := q.db.Query(ctx, listAuctionExtensionsByProductId, productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AuctionExtension
+	for rows.Next() {
+		var i AuctionExtension
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProductID,
+			&i.BidID,
+			&i.PreviousEnd,
+			&i.NewEnd,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,66 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+// source: auction_results.sql
+
+package pgstore
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const createAuctionResult = `-- name: CreateAuctionResult :one
+INSERT INTO auction_results (
+    product_id, winner_id, bid_id, hammer_price, sold_via
+) VALUES ($1, $2, $3, $4, $5)
+    RETURNING product_id, winner_id, bid_id, hammer_price, closed_at, sold_via
+`
+
+type CreateAuctionResultParams struct {
+	ProductID   uuid.UUID `json:"product_id"`
+	WinnerID    uuid.UUID `json:"winner_id"`
+	BidID       uuid.UUID `json:"bid_id"`
+	HammerPrice int64     `json:"hammer_price"`
+	SoldVia     string    `json:"sold_via"`
+}
+
+func (q *Queries) CreateAuctionResult(ctx context.Context, arg CreateAuctionResultParams) (AuctionResult, error) {
+	row := q.db.QueryRow(ctx, createAuctionResult,
+		arg.ProductID,
+		arg.WinnerID,
+		arg.BidID,
+		arg.HammerPrice,
+		arg.SoldVia,
+	)
+	var i AuctionResult
+	err := row.Scan(
+		&i.ProductID,
+		&i.WinnerID,
+		&i.BidID,
+		&i.HammerPrice,
+		&i.ClosedAt,
+		&i.SoldVia,
+	)
+	return i, err
+}
+
+const getAuctionResultByProductId = `-- name: GetAuctionResultByProductId :one
+SELECT product_id, winner_id, bid_id, hammer_price, closed_at, sold_via FROM auction_results
+WHERE product_id = $1
+`
+
+func (q *Queries) GetAuctionResultByProductId(ctx context.Context, productID uuid.UUID) (AuctionResult, error) {
+	row := q.db.QueryRow(ctx, getAuctionResultByProductId, productID)
+	var i AuctionResult
+	err := row.Scan(
+		&i.ProductID,
+		&i.WinnerID,
+		&i.BidID,
+		&i.HammerPrice,
+		&i.ClosedAt,
+		&i.SoldVia,
+	)
+	return i, err
+}
@@ -7,21 +7,38 @@ package pgstore
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const countBidsByProductId = `-- name: CountBidsByProductId :one
+SELECT count(*) FROM bids
+WHERE product_id = $1
+`
+
+func (q *Queries) CountBidsByProductId(ctx context.Context, productID uuid.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, countBidsByProductId, productID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createBid = `-- name: CreateBid :one
+WITH counted AS (
+    UPDATE products SET bid_count = bid_count + 1 WHERE id = $1
+)
 INSERT INTO bids (
     product_id, bidder_id, bid_amount
 ) VALUES ($1, $2, $3)
-    RETURNING id, product_id, bidder_id, bid_amount, created_at
+    RETURNING id, product_id, bidder_id, bid_amount, created_at, seq
 `
 
 type CreateBidParams struct {
 	ProductID uuid.UUID `json:"product_id"`
 	BidderID  uuid.UUID `json:"bidder_id"`
-	BidAmount float64   `json:"bid_amount"`
+	BidAmount int64     `json:"bid_amount"`
 }
 
 func (q *Queries) CreateBid(ctx context.Context, arg CreateBidParams) (Bid, error) {
@@ -33,14 +50,39 @@ func (q *Queries) CreateBid(ctx context.Context, arg CreateBidParams) (Bid, erro
 		&i.BidderID,
 		&i.BidAmount,
 		&i.CreatedAt,
+		&i.Seq,
+	)
+	return i, err
+}
+
+const getBidByProductAndBidder = `-- name: GetBidByProductAndBidder :one
+SELECT id, product_id, bidder_id, bid_amount, created_at, seq FROM bids
+WHERE product_id = $1 AND bidder_id = $2
+`
+
+type GetBidByProductAndBidderParams struct {
+	ProductID uuid.UUID `json:"product_id"`
+	BidderID  uuid.UUID `json:"bidder_id"`
+}
+
+func (q *Queries) GetBidByProductAndBidder(ctx context.Context, arg GetBidByProductAndBidderParams) (Bid, error) {
+	row := q.db.QueryRow(ctx, getBidByProductAndBidder, arg.ProductID, arg.BidderID)
+	var i Bid
+	err := row.Scan(
+		&i.ID,
+		&i.ProductID,
+		&i.BidderID,
+		&i.BidAmount,
+		&i.CreatedAt,
+		&i.Seq,
 	)
 	return i, err
 }
 
 const getBidsByProductId = `-- name: GetBidsByProductId :many
-SELECT id, product_id, bidder_id, bid_amount, created_at FROM bids
+SELECT id, product_id, bidder_id, bid_amount, created_at, seq FROM bids
 WHERE product_id = $1
-ORDER BY bid_amount DESC
+ORDER BY bid_amount DESC, seq ASC
 `
 
 func (q *Queries) GetBidsByProductId(ctx context.Context, productID uuid.UUID) ([]Bid, error) {
@@ -58,6 +100,7 @@ func (q *Queries) GetBidsByProductId(ctx context.Context, productID uuid.UUID) (
 			&i.BidderID,
 			&i.BidAmount,
 			&i.CreatedAt,
+			&i.Seq,
 		); err != nil {
 			return nil, err
 		}
@@ -70,9 +113,9 @@ func (q *Queries) GetBidsByProductId(ctx context.Context, productID uuid.UUID) (
 }
 
 const getHighestBidByProductId = `-- name: GetHighestBidByProductId :one
-SELECT id, product_id, bidder_id, bid_amount, created_at FROM bids
+SELECT id, product_id, bidder_id, bid_amount, created_at, seq FROM bids
 WHERE product_id = $1
-ORDER BY bid_amount DESC
+ORDER BY bid_amount DESC, seq ASC
     LIMIT 1
 `
 
@@ -85,6 +128,93 @@ func (q *Queries) GetHighestBidByProductId(ctx context.Context, productID uuid.U
 		&i.BidderID,
 		&i.BidAmount,
 		&i.CreatedAt,
+		&i.Seq,
+	)
+	return i, err
+}
+
+const listBidsByProductId = `-- name: ListBidsByProductId :many
+SELECT bids.id, bids.product_id, bids.bidder_id, bids.bid_amount, bids.created_at, bids.seq, users.user_name AS bidder_name FROM bids
+JOIN users ON users.id = bids.bidder_id
+WHERE bids.product_id = $1
+  AND ($2::uuid IS NULL OR bids.bidder_id = $2)
+  AND ($3::bigint IS NULL OR bids.seq < $3)
+ORDER BY bids.seq DESC
+LIMIT $4
+`
+
+type ListBidsByProductIdParams struct {
+	ProductID uuid.UUID   `json:"product_id"`
+	BidderID  pgtype.UUID `json:"bidder_id"`
+	BeforeSeq pgtype.Int8 `json:"before_seq"`
+	PageSize  int32       `json:"page_size"`
+}
+
+type ListBidsByProductIdRow struct {
+	ID         uuid.UUID `json:"id"`
+	ProductID  uuid.UUID `json:"product_id"`
+	BidderID   uuid.UUID `json:"bidder_id"`
+	BidAmount  int64     `json:"bid_amount"`
+	CreatedAt  time.Time `json:"created_at"`
+	Seq        int64     `json:"seq"`
+	BidderName string    `json:"bidder_name"`
+}
+
+func (q *Queries) ListBidsByProductId(ctx context.Context, arg ListBidsByProductIdParams) ([]ListBidsByProductIdRow, error) {
+	rows, err := q.db.Query(ctx, listBidsByProductId,
+		arg.ProductID,
+		arg.BidderID,
+		arg.BeforeSeq,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListBidsByProductIdRow
+	for rows.Next() {
+		var i ListBidsByProductIdRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProductID,
+			&i.BidderID,
+			&i.BidAmount,
+			&i.CreatedAt,
+			&i.Seq,
+			&i.BidderName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateBidAmount = `-- name: UpdateBidAmount :one
+UPDATE bids
+SET bid_amount = $2, created_at = now(), seq = nextval('bids_seq_seq')
+WHERE id = $1
+    RETURNING id, product_id, bidder_id, bid_amount, created_at, seq
+`
+
+type UpdateBidAmountParams struct {
+	ID        uuid.UUID `json:"id"`
+	BidAmount int64     `json:"bid_amount"`
+}
+
+func (q *Queries) UpdateBidAmount(ctx context.Context, arg UpdateBidAmountParams) (Bid, error) {
+	row := q.db.QueryRow(ctx, updateBidAmount, arg.ID, arg.BidAmount)
+	var i Bid
+	err := row.Scan(
+		&i.ID,
+		&i.ProductID,
+		&i.BidderID,
+		&i.BidAmount,
+		&i.CreatedAt,
+		&i.Seq,
 	)
 	return i, err
 }
@@ -0,0 +1,195 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+// source: categories.sql
+
+package pgstore
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createCategory = `-- name: CreateCategory :one
+INSERT INTO categories (parent_id, name, slug)
+VALUES ($1, $2, $3)
+RETURNING id, parent_id, name, slug, created_at, updated_at
+`
+
+type CreateCategoryParams struct {
+	ParentID pgtype.UUID `json:"parent_id"`
+	Name     string      `json:"name"`
+	Slug     string      `json:"slug"`
+}
+
+func (q *Queries) CreateCategory(ctx context.Context, arg CreateCategoryParams) (Category, error) {
+	row := q.db.QueryRow(ctx, createCategory, arg.ParentID, arg.Name, arg.Slug)
+	var i Category
+	err := row.Scan(
+		&i.ID,
+		&i.ParentID,
+		&i.Name,
+		&i.Slug,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const deleteCategory = `-- name: DeleteCategory :execrows
+DELETE FROM categories
+WHERE id = $1
+`
+
+func (q *Queries) DeleteCategory(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteCategory, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getCategoryById = `-- name: GetCategoryById :one
+SELECT id, parent_id, name, slug, created_at, updated_at FROM categories
+WHERE id = $1
+`
+
+func (q *Queries) GetCategoryById(ctx context.Context, id uuid.UUID) (Category, error) {
+	row := q.db.QueryRow(ctx, getCategoryById, id)
+	var i Category
+	err := row.Scan(
+		&i.ID,
+		&i.ParentID,
+		&i.Name,
+		&i.Slug,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const isCategoryInSubtree = `-- name: IsCategoryInSubtree :one
+WITH RECURSIVE subtree AS (
+    SELECT id FROM categories WHERE id = $2::uuid
+    UNION ALL
+    SELECT c.id FROM categories c
+    JOIN subtree s ON c.parent_id = s.id
+)
+SELECT EXISTS (SELECT 1 FROM subtree WHERE id = $1::uuid)
+`
+
+type IsCategoryInSubtreeParams struct {
+	CategoryID uuid.UUID `json:"category_id"`
+	RootID     uuid.UUID `json:"root_id"`
+}
+
+func (q *Queries) IsCategoryInSubtree(ctx context.Context, arg IsCategoryInSubtreeParams) (bool, error) {
+	row := q.db.QueryRow(ctx, isCategoryInSubtree, arg.CategoryID, arg.RootID)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const listCategories = `-- name: ListCategories :many
+SELECT id, parent_id, name, slug, created_at, updated_at FROM categories
+ORDER BY name
+`
+
+func (q *Queries) ListCategories(ctx context.Context) ([]Category, error) {
+	rows, err := q.db.Query(ctx, listCategories)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Category
+	for rows.Next() {
+		var i Category
+		if err := rows.Scan(
+			&i.ID,
+			&i.ParentID,
+			&i.Name,
+			&i.Slug,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const subscribeToCategory = `-- name: SubscribeToCategory :exec
+INSERT INTO category_subscriptions (user_id, category_id)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING
+`
+
+type SubscribeToCategoryParams struct {
+	UserID     uuid.UUID `json:"user_id"`
+	CategoryID uuid.UUID `json:"category_id"`
+}
+
+func (q *Queries) SubscribeToCategory(ctx context.Context, arg SubscribeToCategoryParams) error {
+	_, err := q.db.Exec(ctx, subscribeToCategory, arg.UserID, arg.CategoryID)
+	return err
+}
+
+const unsubscribeFromCategory = `-- name: UnsubscribeFromCategory :exec
+DELETE FROM category_subscriptions
+WHERE user_id = $1 AND category_id = $2
+`
+
+type UnsubscribeFromCategoryParams struct {
+	UserID     uuid.UUID `json:"user_id"`
+	CategoryID uuid.UUID `json:"category_id"`
+}
+
+func (q *Queries) UnsubscribeFromCategory(ctx context.Context, arg UnsubscribeFromCategoryParams) error {
+	_, err := q.db.Exec(ctx, unsubscribeFromCategory, arg.UserID, arg.CategoryID)
+	return err
+}
+
+const updateCategory = `-- name: UpdateCategory :one
+UPDATE categories
+SET
+    name = COALESCE($1, name),
+    slug = COALESCE($2, slug),
+    parent_id = CASE WHEN $3::bool THEN $4 ELSE parent_id END,
+    updated_at = now()
+WHERE id = $5
+RETURNING id, parent_id, name, slug, created_at, updated_at
+`
+
+type UpdateCategoryParams struct {
+	Name     pgtype.Text `json:"name"`
+	Slug     pgtype.Text `json:"slug"`
+	Move     bool        `json:"move"`
+	ParentID pgtype.UUID `json:"parent_id"`
+	ID       uuid.UUID   `json:"id"`
+}
+
+func (q *Queries) UpdateCategory(ctx context.Context, arg UpdateCategoryParams) (Category, error) {
+	row := q.db.QueryRow(ctx, updateCategory,
+		arg.Name,
+		arg.Slug,
+		arg.Move,
+		arg.ParentID,
+		arg.ID,
+	)
+	var i Category
+	err := row.Scan(
+		&i.ID,
+		&i.ParentID,
+		&i.Name,
+		&i.Slug,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
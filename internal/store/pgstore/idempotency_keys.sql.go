@@ -0,0 +1,147 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+// source: idempotency_keys.sql
+
+package pgstore
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const completeIdempotencyKey = `-- name: CompleteIdempotencyKey :exec
+UPDATE idempotency_keys
+SET status_code = $3, response_body = $4, completed_at = now()
+WHERE user_id = $1 AND key = $2
+`
+
+type CompleteIdempotencyKeyParams struct {
+	UserID       uuid.UUID   `json:"user_id"`
+	Key          string      `json:"key"`
+	StatusCode   pgtype.Int4 `json:"status_code"`
+	ResponseBody []byte      `json:"response_body"`
+}
+
+func (q *Queries) CompleteIdempotencyKey(ctx context.Context, arg CompleteIdempotencyKeyParams) error {
+	_, err := q.db.Exec(ctx, completeIdempotencyKey,
+		arg.UserID,
+		arg.Key,
+		arg.StatusCode,
+		arg.ResponseBody,
+	)
+	return err
+}
+
+const createIdempotencyKey = `-- name: CreateIdempotencyKey :execrows
+INSERT INTO idempotency_keys (
+    user_id, key, request_hash, locked_until
+) VALUES ($1, $2, $3, $4)
+ON CONFLICT (user_id, key) DO NOTHING
+`
+
+type CreateIdempotencyKeyParams struct {
+	UserID      uuid.UUID `json:"user_id"`
+	Key         string    `json:"key"`
+	RequestHash []byte    `json:"request_hash"`
+	LockedUntil time.Time `json:"locked_until"`
+}
+
+func (q *Queries) CreateIdempotencyKey(ctx context.Context, arg CreateIdempotencyKeyParams) (int64, error) {
+	result, err := q.db.Exec(ctx, createIdempotencyKey,
+		arg.UserID,
+		arg.Key,
+		arg.RequestHash,
+		arg.LockedUntil,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteExpiredIdempotencyKey = `-- name: DeleteExpiredIdempotencyKey :exec
+DELETE FROM idempotency_keys
+WHERE user_id = $1 AND key = $2 AND created_at < $3
+`
+
+type DeleteExpiredIdempotencyKeyParams struct {
+	UserID        uuid.UUID `json:"user_id"`
+	Key           string    `json:"key"`
+	ExpiredBefore time.Time `json:"expired_before"`
+}
+
+func (q *Queries) DeleteExpiredIdempotencyKey(ctx context.Context, arg DeleteExpiredIdempotencyKeyParams) error {
+	_, err := q.db.Exec(ctx, deleteExpiredIdempotencyKey, arg.UserID, arg.Key, arg.ExpiredBefore)
+	return err
+}
+
+const deleteIdempotencyKey = `-- name: DeleteIdempotencyKey :exec
+DELETE FROM idempotency_keys
+WHERE user_id = $1 AND key = $2
+`
+
+type DeleteIdempotencyKeyParams struct {
+	UserID uuid.UUID `json:"user_id"`
+	Key    string    `json:"key"`
+}
+
+func (q *Queries) DeleteIdempotencyKey(ctx context.Context, arg DeleteIdempotencyKeyParams) error {
+	_, err := q.db.Exec(ctx, deleteIdempotencyKey, arg.UserID, arg.Key)
+	return err
+}
+
+const getIdempotencyKey = `-- name: GetIdempotencyKey :one
+SELECT user_id, key, request_hash, status_code, response_body, created_at, completed_at, locked_until FROM idempotency_keys
+WHERE user_id = $1 AND key = $2
+`
+
+type GetIdempotencyKeyParams struct {
+	UserID uuid.UUID `json:"user_id"`
+	Key    string    `json:"key"`
+}
+
+func (q *Queries) GetIdempotencyKey(ctx context.Context, arg GetIdempotencyKeyParams) (IdempotencyKey, error) {
+	row := q.db.QueryRow(ctx, getIdempotencyKey, arg.UserID, arg.Key)
+	var i IdempotencyKey
+	err := row.Scan(
+		&i.UserID,
+		&i.Key,
+		&i.RequestHash,
+		&i.StatusCode,
+		&i.ResponseBody,
+		&i.CreatedAt,
+		&i.CompletedAt,
+		&i.LockedUntil,
+	)
+	return i, err
+}
+
+const takeOverIdempotencyKey = `-- name: TakeOverIdempotencyKey :execrows
+UPDATE idempotency_keys
+SET locked_until = $3
+WHERE user_id = $1 AND key = $2 AND status_code IS NULL AND locked_until < $4
+`
+
+type TakeOverIdempotencyKeyParams struct {
+	UserID      uuid.UUID `json:"user_id"`
+	Key         string    `json:"key"`
+	LockedUntil time.Time `json:"locked_until"`
+	Now         time.Time `json:"now"`
+}
+
+func (q *Queries) TakeOverIdempotencyKey(ctx context.Context, arg TakeOverIdempotencyKeyParams) (int64, error) {
+	result, err := q.db.Exec(ctx, takeOverIdempotencyKey,
+		arg.UserID,
+		arg.Key,
+		arg.LockedUntil,
+		arg.Now,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
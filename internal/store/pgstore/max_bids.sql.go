@@ -0,0 +1,74 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+// source: max_bids.sql
+
+package pgstore
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const getMaxBidsByProductId = `-- name: GetMaxBidsByProductId :many
+SELECT id, product_id, bidder_id, max_amount, created_at, updated_at FROM max_bids
+WHERE product_id = $1
+ORDER BY max_amount DESC, updated_at ASC
+`
+
+func (q *Queries) GetMaxBidsByProductId(ctx context.Context, productID uuid.UUID) ([]MaxBid, error) {
+	rows, err := q.db.Query(ctx, getMaxBidsByProductId, productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []MaxBid
+	for rows.Next() {
+		var i MaxBid
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProductID,
+			&i.BidderID,
+			&i.MaxAmount,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertMaxBid = `-- name: UpsertMaxBid :one
+INSERT INTO max_bids (
+    product_id, bidder_id, max_amount
+) VALUES ($1, $2, $3)
+ON CONFLICT (product_id, bidder_id)
+DO UPDATE SET max_amount = EXCLUDED.max_amount, updated_at = now()
+    RETURNING id, product_id, bidder_id, max_amount, created_at, updated_at
+`
+
+type UpsertMaxBidParams struct {
+	ProductID uuid.UUID `json:"product_id"`
+	BidderID  uuid.UUID `json:"bidder_id"`
+	MaxAmount int64     `json:"max_amount"`
+}
+
+func (q *Queries) UpsertMaxBid(ctx context.Context, arg UpsertMaxBidParams) (MaxBid, error) {
+	row := q.db.QueryRow(ctx, upsertMaxBid, arg.ProductID, arg.BidderID, arg.MaxAmount)
+	var i MaxBid
+	err := row.Scan(
+		&i.ID,
+		&i.ProductID,
+		&i.BidderID,
+		&i.MaxAmount,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,14 @@
+-- Write your migrate up statements here
+CREATE TABLE IF NOT EXISTS auction_results (
+    product_id UUID PRIMARY KEY REFERENCES products (id),
+    winner_id UUID NOT NULL REFERENCES users (id),
+    bid_id UUID NOT NULL REFERENCES bids (id),
+    hammer_price FLOAT NOT NULL,
+    closed_at TIMESTAMPTZ NOT NULL DEFAULT now()
+    );
+
+---- create above / drop below ----
+DROP TABLE IF EXISTS auction_results;
+
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
@@ -0,0 +1,12 @@
+-- Write your migrate up statements here
+ALTER TABLE products
+    ADD COLUMN increment_type TEXT NOT NULL DEFAULT 'fixed',
+    ADD COLUMN increment_value FLOAT NOT NULL DEFAULT 1;
+
+---- create above / drop below ----
+ALTER TABLE products
+    DROP COLUMN IF EXISTS increment_value,
+    DROP COLUMN IF EXISTS increment_type;
+
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
@@ -0,0 +1,16 @@
+-- Write your migrate up statements here
+ALTER TABLE products
+    ADD COLUMN buy_now_price FLOAT NOT NULL DEFAULT 0;
+
+ALTER TABLE auction_results
+    ADD COLUMN sold_via TEXT NOT NULL DEFAULT 'auction';
+
+---- create above / drop below ----
+ALTER TABLE auction_results
+    DROP COLUMN IF EXISTS sold_via;
+
+ALTER TABLE products
+    DROP COLUMN IF EXISTS buy_now_price;
+
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
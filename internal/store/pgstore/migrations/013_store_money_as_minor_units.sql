@@ -0,0 +1,61 @@
+-- Write your migrate up statements here
+ALTER TABLE products
+    ALTER COLUMN increment_value DROP DEFAULT,
+    ALTER COLUMN reserve_price DROP DEFAULT,
+    ALTER COLUMN buy_now_price DROP DEFAULT,
+    ALTER COLUMN dutch_start_price DROP DEFAULT,
+    ALTER COLUMN dutch_floor_price DROP DEFAULT,
+    ALTER COLUMN dutch_price_step DROP DEFAULT;
+
+ALTER TABLE products
+    ALTER COLUMN baseprice TYPE BIGINT USING round(baseprice * 100)::BIGINT,
+    ALTER COLUMN increment_value TYPE BIGINT USING round(increment_value * 100)::BIGINT,
+    ALTER COLUMN reserve_price TYPE BIGINT USING round(reserve_price * 100)::BIGINT,
+    ALTER COLUMN buy_now_price TYPE BIGINT USING round(buy_now_price * 100)::BIGINT,
+    ALTER COLUMN dutch_start_price TYPE BIGINT USING round(dutch_start_price * 100)::BIGINT,
+    ALTER COLUMN dutch_floor_price TYPE BIGINT USING round(dutch_floor_price * 100)::BIGINT,
+    ALTER COLUMN dutch_price_step TYPE BIGINT USING round(dutch_price_step * 100)::BIGINT,
+    ADD COLUMN currency TEXT NOT NULL DEFAULT 'USD';
+
+ALTER TABLE products
+    ALTER COLUMN increment_value SET DEFAULT 100,
+    ALTER COLUMN reserve_price SET DEFAULT 0,
+    ALTER COLUMN buy_now_price SET DEFAULT 0,
+    ALTER COLUMN dutch_start_price SET DEFAULT 0,
+    ALTER COLUMN dutch_floor_price SET DEFAULT 0,
+    ALTER COLUMN dutch_price_step SET DEFAULT 0;
+
+ALTER TABLE bids
+    ALTER COLUMN bid_amount TYPE BIGINT USING round(bid_amount * 100)::BIGINT;
+
+ALTER TABLE max_bids
+    ALTER COLUMN max_amount TYPE BIGINT USING round(max_amount * 100)::BIGINT;
+
+ALTER TABLE auction_results
+    ALTER COLUMN hammer_price TYPE BIGINT USING round(hammer_price * 100)::BIGINT;
+
+---- create above / drop below ----
+ALTER TABLE auction_results
+    ALTER COLUMN hammer_price TYPE FLOAT USING hammer_price / 100.0;
+
+ALTER TABLE max_bids
+    ALTER COLUMN max_amount TYPE FLOAT USING max_amount / 100.0;
+
+ALTER TABLE bids
+    ALTER COLUMN bid_amount TYPE FLOAT USING bid_amount / 100.0;
+
+ALTER TABLE products
+    DROP COLUMN IF EXISTS currency,
+    ALTER COLUMN baseprice TYPE FLOAT USING baseprice / 100.0,
+    ALTER COLUMN increment_value TYPE FLOAT USING increment_value / 100.0,
+    ALTER COLUMN reserve_price TYPE FLOAT USING reserve_price / 100.0,
+    ALTER COLUMN buy_now_price TYPE FLOAT USING buy_now_price / 100.0,
+    ALTER COLUMN dutch_start_price TYPE FLOAT USING dutch_start_price / 100.0,
+    ALTER COLUMN dutch_floor_price TYPE FLOAT USING dutch_floor_price / 100.0,
+    ALTER COLUMN dutch_price_step TYPE FLOAT USING dutch_price_step / 100.0;
+
+ALTER TABLE products
+    ALTER COLUMN increment_value SET DEFAULT 1;
+
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
@@ -0,0 +1,17 @@
+-- Write your migrate up statements here
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    key TEXT NOT NULL,
+    request_hash BYTEA NOT NULL,
+    status_code INTEGER,
+    response_body BYTEA,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    completed_at TIMESTAMPTZ,
+    PRIMARY KEY (user_id, key)
+    );
+
+---- create above / drop below ----
+DROP TABLE IF EXISTS idempotency_keys;
+
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
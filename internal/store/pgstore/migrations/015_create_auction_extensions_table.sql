@@ -0,0 +1,19 @@
+-- Write your migrate up statements here
+CREATE TABLE IF NOT EXISTS auction_extensions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    product_id UUID NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+    bid_id UUID NOT NULL REFERENCES bids (id) ON DELETE CASCADE,
+    previous_end TIMESTAMPTZ NOT NULL,
+    new_end TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+    );
+
+CREATE INDEX IF NOT EXISTS auction_extensions_product_id_idx ON auction_extensions (product_id, created_at);
+CREATE INDEX IF NOT EXISTS bids_product_id_created_at_idx ON bids (product_id, created_at DESC, id DESC);
+
+---- create above / drop below ----
+DROP INDEX IF EXISTS bids_product_id_created_at_idx;
+DROP TABLE IF EXISTS auction_extensions;
+
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
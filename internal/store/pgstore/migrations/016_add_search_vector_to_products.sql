@@ -0,0 +1,19 @@
+-- Write your migrate up statements here
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS search_vector TSVECTOR GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', product_name), 'A') ||
+        setweight(to_tsvector('english', description), 'B')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS products_search_vector_idx ON products USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS products_auction_end_idx ON products (auction_end, id);
+CREATE INDEX IF NOT EXISTS products_created_at_idx ON products (created_at DESC, id DESC);
+
+---- create above / drop below ----
+DROP INDEX IF EXISTS products_created_at_idx;
+DROP INDEX IF EXISTS products_auction_end_idx;
+DROP INDEX IF EXISTS products_search_vector_idx;
+ALTER TABLE products DROP COLUMN IF EXISTS search_vector;
+
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
@@ -0,0 +1,57 @@
+-- Write your migrate up statements here
+ALTER TABLE users ADD COLUMN IF NOT EXISTS is_admin BOOLEAN NOT NULL DEFAULT false;
+
+CREATE TABLE IF NOT EXISTS categories (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    parent_id UUID REFERENCES categories (id) ON DELETE RESTRICT,
+    name VARCHAR(80) NOT NULL,
+    slug VARCHAR(80) UNIQUE NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+    );
+
+CREATE INDEX IF NOT EXISTS categories_parent_id_idx ON categories (parent_id);
+
+CREATE TABLE IF NOT EXISTS tags (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(50) UNIQUE NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+    );
+
+ALTER TABLE products ADD COLUMN IF NOT EXISTS category_id UUID REFERENCES categories (id) ON DELETE RESTRICT;
+CREATE INDEX IF NOT EXISTS products_category_id_idx ON products (category_id);
+
+CREATE TABLE IF NOT EXISTS product_tags (
+    product_id UUID NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+    tag_id UUID NOT NULL REFERENCES tags (id) ON DELETE CASCADE,
+    PRIMARY KEY (product_id, tag_id)
+    );
+
+CREATE INDEX IF NOT EXISTS product_tags_tag_id_idx ON product_tags (tag_id);
+
+CREATE TABLE IF NOT EXISTS category_subscriptions (
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    category_id UUID NOT NULL REFERENCES categories (id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, category_id)
+    );
+
+CREATE TABLE IF NOT EXISTS tag_subscriptions (
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    tag_id UUID NOT NULL REFERENCES tags (id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, tag_id)
+    );
+
+---- create above / drop below ----
+DROP TABLE IF EXISTS tag_subscriptions;
+DROP TABLE IF EXISTS category_subscriptions;
+DROP TABLE IF EXISTS product_tags;
+DROP INDEX IF EXISTS products_category_id_idx;
+ALTER TABLE products DROP COLUMN IF EXISTS category_id;
+DROP TABLE IF EXISTS tags;
+DROP TABLE IF EXISTS categories;
+ALTER TABLE users DROP COLUMN IF EXISTS is_admin;
+
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
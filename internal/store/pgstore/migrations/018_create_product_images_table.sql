@@ -0,0 +1,20 @@
+-- Write your migrate up statements here
+CREATE TABLE IF NOT EXISTS product_images (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    product_id UUID NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    content_type TEXT NOT NULL,
+    original_key TEXT NOT NULL,
+    thumbnail_key TEXT NOT NULL,
+    width INTEGER NOT NULL,
+    height INTEGER NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT product_images_position_key UNIQUE (product_id, position) DEFERRABLE INITIALLY DEFERRED
+    );
+
+---- create above / drop below ----
+DROP TABLE IF EXISTS product_images;
+
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
@@ -0,0 +1,40 @@
+-- Write your migrate up statements here
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'scheduled'
+        CHECK (status IN ('draft', 'scheduled', 'live', 'ended', 'sold', 'cancelled', 'paid'));
+
+-- Auctions past their end stay live until settlement decides whether they
+-- sold.
+UPDATE products
+SET status = CASE
+    WHEN is_sold THEN 'sold'
+    WHEN auction_start <= now() THEN 'live'
+    ELSE 'scheduled'
+END;
+
+ALTER TABLE products DROP COLUMN IF EXISTS is_sold;
+CREATE INDEX IF NOT EXISTS products_status_idx ON products (status);
+
+CREATE TABLE IF NOT EXISTS product_status_transitions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    product_id UUID NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+    from_status TEXT,
+    to_status TEXT NOT NULL,
+    actor_id UUID REFERENCES users (id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+    );
+
+CREATE INDEX IF NOT EXISTS product_status_transitions_product_id_idx ON product_status_transitions (product_id, created_at);
+
+INSERT INTO product_status_transitions (product_id, from_status, to_status, created_at)
+SELECT id, NULL, status, updated_at FROM products;
+
+---- create above / drop below ----
+DROP TABLE IF EXISTS product_status_transitions;
+ALTER TABLE products ADD COLUMN IF NOT EXISTS is_sold BOOLEAN NOT NULL DEFAULT false;
+UPDATE products SET is_sold = status IN ('sold', 'paid');
+DROP INDEX IF EXISTS products_status_idx;
+ALTER TABLE products DROP COLUMN IF EXISTS status;
+
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
@@ -0,0 +1,11 @@
+-- Write your migrate up statements here
+-- A pending key is only held until its lease runs out, so a request that
+-- died without releasing it can be retried.
+ALTER TABLE idempotency_keys
+    ADD COLUMN IF NOT EXISTS locked_until TIMESTAMPTZ NOT NULL DEFAULT now();
+
+---- create above / drop below ----
+ALTER TABLE idempotency_keys DROP COLUMN IF EXISTS locked_until;
+
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
@@ -0,0 +1,23 @@
+-- Write your migrate up statements here
+-- Bids placed in one transaction, like a bid and the automatic bids it
+-- triggers, share created_at. seq records the order they were placed in.
+ALTER TABLE bids ADD COLUMN IF NOT EXISTS seq BIGINT;
+CREATE SEQUENCE IF NOT EXISTS bids_seq_seq OWNED BY bids.seq;
+
+UPDATE bids
+SET seq = ordered.position
+FROM (SELECT id, row_number() OVER (ORDER BY created_at, id) AS position FROM bids) ordered
+WHERE bids.id = ordered.id;
+
+SELECT setval('bids_seq_seq', COALESCE((SELECT max(seq) FROM bids), 0) + 1, false);
+ALTER TABLE bids
+    ALTER COLUMN seq SET DEFAULT nextval('bids_seq_seq'),
+    ALTER COLUMN seq SET NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS bids_product_id_seq_idx ON bids (product_id, seq);
+
+---- create above / drop below ----
+DROP INDEX IF EXISTS bids_product_id_seq_idx;
+ALTER TABLE bids DROP COLUMN IF EXISTS seq;
+
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
@@ -0,0 +1,64 @@
+-- Write your migrate up statements here
+-- bid_count is kept by CreateBid so the catalogue can page through the most
+-- bid on auctions with an index instead of counting every product's bids.
+ALTER TABLE products ADD COLUMN IF NOT EXISTS bid_count BIGINT NOT NULL DEFAULT 0;
+
+UPDATE products
+SET bid_count = counted.bid_count
+FROM (SELECT product_id, count(*) AS bid_count FROM bids GROUP BY product_id) counted
+WHERE products.id = counted.product_id;
+
+CREATE INDEX IF NOT EXISTS products_bid_count_idx ON products (bid_count DESC, id DESC);
+
+-- product_catalogue is the public view of the listings. Its sort columns come
+-- straight from products, so the catalogue queries page on their indexes.
+CREATE OR REPLACE VIEW product_catalogue AS
+SELECT
+    p.id, p.seller_id, p.product_name, p.description, p.baseprice,
+    p.buy_now_price, p.auction_type, p.auction_start, p.auction_end,
+    p.currency, p.created_at, p.category_id, p.search_vector, p.bid_count,
+    (CASE
+        WHEN p.status IN ('scheduled', 'live') AND p.auction_end <= now() THEN 'ended'
+        WHEN p.status = 'scheduled' AND p.auction_start <= now() THEN 'live'
+        ELSE p.status
+    END)::text AS status,
+    (CASE
+        WHEN p.auction_type IN ('sealed', 'vickrey') THEN p.baseprice
+        -- A dutch auction's only bid is the one that won it; until then
+        -- the price follows the schedule, as DutchSchedule.PriceAt does.
+        WHEN p.auction_type = 'dutch' THEN COALESCE(b.high_bid, GREATEST(
+            p.dutch_floor_price,
+            p.dutch_start_price - (CASE
+                WHEN p.dutch_step_interval_seconds > 0 AND p.auction_start <= now()
+                THEN floor(extract(epoch FROM now() - p.auction_start) / p.dutch_step_interval_seconds)::bigint
+                ELSE 0
+            END) * p.dutch_price_step))
+        ELSE COALESCE(b.high_bid, p.baseprice)
+    END)::bigint AS current_price,
+    ARRAY(
+        SELECT t.name FROM product_tags pt
+        JOIN tags t ON t.id = pt.tag_id
+        WHERE pt.product_id = p.id
+        ORDER BY t.name
+    )::text[] AS tags,
+    COALESCE((
+        SELECT pi.thumbnail_key FROM product_images pi
+        WHERE pi.product_id = p.id
+        ORDER BY pi.position
+        LIMIT 1
+    ), '')::text AS cover_thumbnail_key
+FROM products p
+LEFT JOIN LATERAL (
+    SELECT max(bid_amount) AS high_bid
+    FROM bids
+    WHERE bids.product_id = p.id
+) b ON true
+WHERE p.status <> 'draft';
+
+---- create above / drop below ----
+DROP VIEW IF EXISTS product_catalogue;
+DROP INDEX IF EXISTS products_bid_count_idx;
+ALTER TABLE products DROP COLUMN IF EXISTS bid_count;
+
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
@@ -0,0 +1,18 @@
+-- Write your migrate up statements here
+CREATE TABLE IF NOT EXISTS notifications (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    product_id UUID NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+    kind VARCHAR(40) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    read_at TIMESTAMPTZ,
+    UNIQUE (user_id, product_id, kind)
+    );
+
+CREATE INDEX IF NOT EXISTS notifications_user_id_created_at_idx ON notifications (user_id, created_at DESC, id DESC);
+
+---- create above / drop below ----
+DROP TABLE IF EXISTS notifications;
+
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
@@ -8,26 +8,154 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type AuctionExtension struct {
+	ID          uuid.UUID `json:"id"`
+	ProductID   uuid.UUID `json:"product_id"`
+	BidID       uuid.UUID `json:"bid_id"`
+	PreviousEnd time.Time `json:"previous_end"`
+	NewEnd      time.Time `json:"new_end"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type AuctionResult struct {
+	ProductID   uuid.UUID `json:"product_id"`
+	WinnerID    uuid.UUID `json:"winner_id"`
+	BidID       uuid.UUID `json:"bid_id"`
+	HammerPrice int64     `json:"hammer_price"`
+	ClosedAt    time.Time `json:"closed_at"`
+	SoldVia     string    `json:"sold_via"`
+}
+
 type Bid struct {
 	ID        uuid.UUID `json:"id"`
 	ProductID uuid.UUID `json:"product_id"`
 	BidderID  uuid.UUID `json:"bidder_id"`
-	BidAmount float64   `json:"bid_amount"`
+	BidAmount int64     `json:"bid_amount"`
+	CreatedAt time.Time `json:"created_at"`
+	Seq       int64     `json:"seq"`
+}
+
+type Category struct {
+	ID        uuid.UUID   `json:"id"`
+	ParentID  pgtype.UUID `json:"parent_id"`
+	Name      string      `json:"name"`
+	Slug      string      `json:"slug"`
+	CreatedAt time.Time   `json:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+type CategorySubscription struct {
+	UserID     uuid.UUID `json:"user_id"`
+	CategoryID uuid.UUID `json:"category_id"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type IdempotencyKey struct {
+	UserID       uuid.UUID          `json:"user_id"`
+	Key          string             `json:"key"`
+	RequestHash  []byte             `json:"request_hash"`
+	StatusCode   pgtype.Int4        `json:"status_code"`
+	ResponseBody []byte             `json:"response_body"`
+	CreatedAt    time.Time          `json:"created_at"`
+	CompletedAt  pgtype.Timestamptz `json:"completed_at"`
+	LockedUntil  time.Time          `json:"locked_until"`
+}
+
+type MaxBid struct {
+	ID        uuid.UUID `json:"id"`
+	ProductID uuid.UUID `json:"product_id"`
+	BidderID  uuid.UUID `json:"bidder_id"`
+	MaxAmount int64     `json:"max_amount"`
 	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type Notification struct {
+	ID        uuid.UUID          `json:"id"`
+	UserID    uuid.UUID          `json:"user_id"`
+	ProductID uuid.UUID          `json:"product_id"`
+	Kind      string             `json:"kind"`
+	CreatedAt time.Time          `json:"created_at"`
+	ReadAt    pgtype.Timestamptz `json:"read_at"`
 }
 
 type Product struct {
-	ID          uuid.UUID `json:"id"`
-	SellerID    uuid.UUID `json:"seller_id"`
-	ProductName string    `json:"product_name"`
-	Description string    `json:"description"`
-	Baseprice   float64   `json:"baseprice"`
-	AuctionEnd  time.Time `json:"auction_end"`
-	IsSold      bool      `json:"is_sold"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID                        uuid.UUID   `json:"id"`
+	SellerID                  uuid.UUID   `json:"seller_id"`
+	ProductName               string      `json:"product_name"`
+	Description               string      `json:"description"`
+	Baseprice                 int64       `json:"baseprice"`
+	AuctionEnd                time.Time   `json:"auction_end"`
+	CreatedAt                 time.Time   `json:"created_at"`
+	UpdatedAt                 time.Time   `json:"updated_at"`
+	SoftCloseWindowMinutes    int32       `json:"soft_close_window_minutes"`
+	SoftCloseExtensionMinutes int32       `json:"soft_close_extension_minutes"`
+	IncrementType             string      `json:"increment_type"`
+	IncrementValue            int64       `json:"increment_value"`
+	ReservePrice              int64       `json:"reserve_price"`
+	BuyNowPrice               int64       `json:"buy_now_price"`
+	AuctionType               string      `json:"auction_type"`
+	DutchStartPrice           int64       `json:"dutch_start_price"`
+	DutchFloorPrice           int64       `json:"dutch_floor_price"`
+	DutchPriceStep            int64       `json:"dutch_price_step"`
+	DutchStepIntervalSeconds  int32       `json:"dutch_step_interval_seconds"`
+	AuctionStart              time.Time   `json:"auction_start"`
+	Currency                  string      `json:"currency"`
+	SearchVector              string      `json:"-"`
+	CategoryID                pgtype.UUID `json:"category_id"`
+	Status                    string      `json:"status"`
+	BidCount                  int64       `json:"bid_count"`
+}
+
+type ProductCatalogue struct {
+	ID                uuid.UUID   `json:"id"`
+	SellerID          uuid.UUID   `json:"seller_id"`
+	ProductName       string      `json:"product_name"`
+	Description       string      `json:"description"`
+	Baseprice         int64       `json:"baseprice"`
+	BuyNowPrice       int64       `json:"buy_now_price"`
+	AuctionType       string      `json:"auction_type"`
+	AuctionStart      time.Time   `json:"auction_start"`
+	AuctionEnd        time.Time   `json:"auction_end"`
+	Currency          string      `json:"currency"`
+	CreatedAt         time.Time   `json:"created_at"`
+	CategoryID        pgtype.UUID `json:"category_id"`
+	SearchVector      string      `json:"-"`
+	BidCount          int64       `json:"bid_count"`
+	Status            string      `json:"status"`
+	CurrentPrice      int64       `json:"current_price"`
+	Tags              []string    `json:"tags"`
+	CoverThumbnailKey string      `json:"cover_thumbnail_key"`
+}
+
+type ProductImage struct {
+	ID           uuid.UUID `json:"id"`
+	ProductID    uuid.UUID `json:"product_id"`
+	Position     int32     `json:"position"`
+	ContentType  string    `json:"content_type"`
+	OriginalKey  string    `json:"original_key"`
+	ThumbnailKey string    `json:"thumbnail_key"`
+	Width        int32     `json:"width"`
+	Height       int32     `json:"height"`
+	SizeBytes    int64     `json:"size_bytes"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type ProductStatusTransition struct {
+	ID         uuid.UUID   `json:"id"`
+	ProductID  uuid.UUID   `json:"product_id"`
+	FromStatus pgtype.Text `json:"from_status"`
+	ToStatus   string      `json:"to_status"`
+	ActorID    pgtype.UUID `json:"actor_id"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
+
+type ProductTag struct {
+	ProductID uuid.UUID `json:"product_id"`
+	TagID     uuid.UUID `json:"tag_id"`
 }
 
 type Session struct {
@@ -36,6 +164,18 @@ type Session struct {
 	Expiry time.Time `json:"expiry"`
 }
 
+type Tag struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type TagSubscription struct {
+	UserID    uuid.UUID `json:"user_id"`
+	TagID     uuid.UUID `json:"tag_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
 type User struct {
 	ID           uuid.UUID `json:"id"`
 	UserName     string    `json:"user_name"`
@@ -44,4 +184,5 @@ type User struct {
 	Bio          string    `json:"bio"`
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
+	IsAdmin      bool      `json:"is_admin"`
 }
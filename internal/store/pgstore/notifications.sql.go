@@ -0,0 +1,95 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+// source: notifications.sql
+
+package pgstore
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createNotifications = `-- name: CreateNotifications :execrows
+INSERT INTO notifications (user_id, product_id, kind)
+SELECT unnest($1::uuid[]), $2, $3
+ON CONFLICT DO NOTHING
+`
+
+type CreateNotificationsParams struct {
+	UserIds   []uuid.UUID `json:"user_ids"`
+	ProductID uuid.UUID   `json:"product_id"`
+	Kind      string      `json:"kind"`
+}
+
+func (q *Queries) CreateNotifications(ctx context.Context, arg CreateNotificationsParams) (int64, error) {
+	result, err := q.db.Exec(ctx, createNotifications, arg.UserIds, arg.ProductID, arg.Kind)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const listNotificationsByUserId = `-- name: ListNotificationsByUserId :many
+SELECT n.id, n.product_id, n.kind, n.created_at, n.read_at, p.product_name
+FROM notifications n
+JOIN products p ON p.id = n.product_id
+WHERE n.user_id = $1
+ORDER BY n.created_at DESC, n.id DESC
+LIMIT $2
+`
+
+type ListNotificationsByUserIdParams struct {
+	UserID uuid.UUID `json:"user_id"`
+	Limit  int32     `json:"limit"`
+}
+
+type ListNotificationsByUserIdRow struct {
+	ID          uuid.UUID          `json:"id"`
+	ProductID   uuid.UUID          `json:"product_id"`
+	Kind        string             `json:"kind"`
+	CreatedAt   time.Time          `json:"created_at"`
+	ReadAt      pgtype.Timestamptz `json:"read_at"`
+	ProductName string             `json:"product_name"`
+}
+
+func (q *Queries) ListNotificationsByUserId(ctx context.Context, arg ListNotificationsByUserIdParams) ([]ListNotificationsByUserIdRow, error) {
+	rows, err := q.db.Query(ctx, listNotificationsByUserId, arg.UserID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListNotificationsByUserIdRow
+	for rows.Next() {
+		var i ListNotificationsByUserIdRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProductID,
+			&i.Kind,
+			&i.CreatedAt,
+			&i.ReadAt,
+			&i.ProductName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markNotificationsRead = `-- name: MarkNotificationsRead :exec
+UPDATE notifications
+SET read_at = now()
+WHERE user_id = $1 AND read_at IS NULL
+`
+
+func (q *Queries) MarkNotificationsRead(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, markNotificationsRead, userID)
+	return err
+}
@@ -0,0 +1,155 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+// source: product_images.sql
+
+package pgstore
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const countProductImages = `-- name: CountProductImages :one
+SELECT count(*) FROM product_images
+WHERE product_id = $1
+`
+
+func (q *Queries) CountProductImages(ctx context.Context, productID uuid.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, countProductImages, productID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createProductImage = `-- name: CreateProductImage :one
+INSERT INTO product_images (
+    id, product_id, position, content_type, original_key, thumbnail_key, width, height, size_bytes
+) VALUES (
+    $1, $2,
+    (SELECT COALESCE(max(position) + 1, 0) FROM product_images WHERE product_id = $2),
+    $3, $4, $5, $6, $7, $8
+)
+RETURNING id, product_id, position, content_type, original_key, thumbnail_key, width, height, size_bytes, created_at
+`
+
+type CreateProductImageParams struct {
+	ID           uuid.UUID `json:"id"`
+	ProductID    uuid.UUID `json:"product_id"`
+	ContentType  string    `json:"content_type"`
+	OriginalKey  string    `json:"original_key"`
+	ThumbnailKey string    `json:"thumbnail_key"`
+	Width        int32     `json:"width"`
+	Height       int32     `json:"height"`
+	SizeBytes    int64     `json:"size_bytes"`
+}
+
+func (q *Queries) CreateProductImage(ctx context.Context, arg CreateProductImageParams) (ProductImage, error) {
+	row := q.db.QueryRow(ctx, createProductImage,
+		arg.ID,
+		arg.ProductID,
+		arg.ContentType,
+		arg.OriginalKey,
+		arg.ThumbnailKey,
+		arg.Width,
+		arg.Height,
+		arg.SizeBytes,
+	)
+	var i ProductImage
+	err := row.Scan(
+		&i.ID,
+		&i.ProductID,
+		&i.Position,
+		&i.ContentType,
+		&i.OriginalKey,
+		&i.ThumbnailKey,
+		&i.Width,
+		&i.Height,
+		&i.SizeBytes,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteProductImage = `-- name: DeleteProductImage :one
+DELETE FROM product_images
+WHERE id = $1 AND product_id = $2
+RETURNING id, product_id, position, content_type, original_key, thumbnail_key, width, height, size_bytes, created_at
+`
+
+type DeleteProductImageParams struct {
+	ID        uuid.UUID `json:"id"`
+	ProductID uuid.UUID `json:"product_id"`
+}
+
+func (q *Queries) DeleteProductImage(ctx context.Context, arg DeleteProductImageParams) (ProductImage, error) {
+	row := q.db.QueryRow(ctx, deleteProductImage, arg.ID, arg.ProductID)
+	var i ProductImage
+	err := row.Scan(
+		&i.ID,
+		&i.ProductID,
+		&i.Position,
+		&i.ContentType,
+		&i.OriginalKey,
+		&i.ThumbnailKey,
+		&i.Width,
+		&i.Height,
+		&i.SizeBytes,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listProductImages = `-- name: ListProductImages :many
+SELECT id, product_id, position, content_type, original_key, thumbnail_key, width, height, size_bytes, created_at FROM product_images
+WHERE product_id = $1
+ORDER BY position
+`
+
+func (q *Queries) ListProductImages(ctx context.Context, productID uuid.UUID) ([]ProductImage, error) {
+	rows, err := q.db.Query(ctx, listProductImages, productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProductImage
+	for rows.Next() {
+		var i ProductImage
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProductID,
+			&i.Position,
+			&i.ContentType,
+			&i.OriginalKey,
+			&i.ThumbnailKey,
+			&i.Width,
+			&i.Height,
+			&i.SizeBytes,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const reorderProductImages = `-- name: ReorderProductImages :exec
+UPDATE product_images
+SET position = array_position($1::uuid[], id) - 1
+WHERE product_id = $2
+`
+
+type ReorderProductImagesParams struct {
+	ImageIds  []uuid.UUID `json:"image_ids"`
+	ProductID uuid.UUID   `json:"product_id"`
+}
+
+func (q *Queries) ReorderProductImages(ctx context.Context, arg ReorderProductImagesParams) error {
+	_, err := q.db.Exec(ctx, reorderProductImages, arg.ImageIds, arg.ProductID)
+	return err
+}
@@ -0,0 +1,71 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+// source: product_status_transitions.sql
+
+package pgstore
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createProductStatusTransition = `-- name: CreateProductStatusTransition :exec
+INSERT INTO product_status_transitions (product_id, from_status, to_status, actor_id, created_at)
+VALUES ($1, $2, $3, $4, $5)
+`
+
+type CreateProductStatusTransitionParams struct {
+	ProductID  uuid.UUID   `json:"product_id"`
+	FromStatus pgtype.Text `json:"from_status"`
+	ToStatus   string      `json:"to_status"`
+	ActorID    pgtype.UUID `json:"actor_id"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
+
+func (q *Queries) CreateProductStatusTransition(ctx context.Context, arg CreateProductStatusTransitionParams) error {
+	_, err := q.db.Exec(ctx, createProductStatusTransition,
+		arg.ProductID,
+		arg.FromStatus,
+		arg.ToStatus,
+		arg.ActorID,
+		arg.CreatedAt,
+	)
+	return err
+}
+
+const listProductStatusTransitions = `-- name: ListProductStatusTransitions :many
+SELECT id, product_id, from_status, to_status, actor_id, created_at FROM product_status_transitions
+WHERE product_id = $1
+ORDER BY created_at, id
+`
+
+func (q *Queries) ListProductStatusTransitions(ctx context.Context, productID uuid.UUID) ([]ProductStatusTransition, error) {
+	rows, err := q.db.Query(ctx, listProductStatusTransitions, productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProductStatusTransition
+	for rows.Next() {
+		var i ProductStatusTransition
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProductID,
+			&i.FromStatus,
+			&i.ToStatus,
+			&i.ActorID,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -10,21 +10,41 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const createdProduct = `-- name: CreatedProduct :one
 INSERT INTO products (
-    product_name, seller_id, description, baseprice, auction_end
-) VALUES ($1, $2, $3, $4, $5)
+    product_name, seller_id, description, baseprice, auction_end,
+    soft_close_window_minutes, soft_close_extension_minutes,
+    increment_type, increment_value, reserve_price, buy_now_price,
+    auction_type, dutch_start_price, dutch_floor_price, dutch_price_step,
+    dutch_step_interval_seconds, auction_start, currency, category_id, status
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)
 RETURNING id
 `
 
 type CreatedProductParams struct {
-	ProductName string    `json:"product_name"`
-	SellerID    uuid.UUID `json:"seller_id"`
-	Description string    `json:"description"`
-	Baseprice   float64   `json:"baseprice"`
-	AuctionEnd  time.Time `json:"auction_end"`
+	ProductName               string      `json:"product_name"`
+	SellerID                  uuid.UUID   `json:"seller_id"`
+	Description               string      `json:"description"`
+	Baseprice                 int64       `json:"baseprice"`
+	AuctionEnd                time.Time   `json:"auction_end"`
+	SoftCloseWindowMinutes    int32       `json:"soft_close_window_minutes"`
+	SoftCloseExtensionMinutes int32       `json:"soft_close_extension_minutes"`
+	IncrementType             string      `json:"increment_type"`
+	IncrementValue            int64       `json:"increment_value"`
+	ReservePrice              int64       `json:"reserve_price"`
+	BuyNowPrice               int64       `json:"buy_now_price"`
+	AuctionType               string      `json:"auction_type"`
+	DutchStartPrice           int64       `json:"dutch_start_price"`
+	DutchFloorPrice           int64       `json:"dutch_floor_price"`
+	DutchPriceStep            int64       `json:"dutch_price_step"`
+	DutchStepIntervalSeconds  int32       `json:"dutch_step_interval_seconds"`
+	AuctionStart              time.Time   `json:"auction_start"`
+	Currency                  string      `json:"currency"`
+	CategoryID                pgtype.UUID `json:"category_id"`
+	Status                    string      `json:"status"`
 }
 
 func (q *Queries) CreatedProduct(ctx context.Context, arg CreatedProductParams) (uuid.UUID, error) {
@@ -34,6 +54,21 @@ func (q *Queries) CreatedProduct(ctx context.Context, arg CreatedProductParams)
 		arg.Description,
 		arg.Baseprice,
 		arg.AuctionEnd,
+		arg.SoftCloseWindowMinutes,
+		arg.SoftCloseExtensionMinutes,
+		arg.IncrementType,
+		arg.IncrementValue,
+		arg.ReservePrice,
+		arg.BuyNowPrice,
+		arg.AuctionType,
+		arg.DutchStartPrice,
+		arg.DutchFloorPrice,
+		arg.DutchPriceStep,
+		arg.DutchStepIntervalSeconds,
+		arg.AuctionStart,
+		arg.Currency,
+		arg.CategoryID,
+		arg.Status,
 	)
 	var id uuid.UUID
 	err := row.Scan(&id)
@@ -56,7 +91,7 @@ func (q *Queries) DeleteProduct(ctx context.Context, arg DeleteProductParams) er
 }
 
 const getProductById = `-- name: GetProductById :one
-SELECT id, seller_id, product_name, description, baseprice, auction_end, is_sold, created_at, updated_at FROM products
+SELECT id, seller_id, product_name, description, baseprice, auction_end, created_at, updated_at, soft_close_window_minutes, soft_close_extension_minutes, increment_type, increment_value, reserve_price, buy_now_price, auction_type, dutch_start_price, dutch_floor_price, dutch_price_step, dutch_step_interval_seconds, auction_start, currency, search_vector, category_id, status, bid_count FROM products
 WHERE id = $1
 `
 
@@ -70,40 +105,542 @@ func (q *Queries) GetProductById(ctx context.Context, id uuid.UUID) (Product, er
 		&i.Description,
 		&i.Baseprice,
 		&i.AuctionEnd,
-		&i.IsSold,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.SoftCloseWindowMinutes,
+		&i.SoftCloseExtensionMinutes,
+		&i.IncrementType,
+		&i.IncrementValue,
+		&i.ReservePrice,
+		&i.BuyNowPrice,
+		&i.AuctionType,
+		&i.DutchStartPrice,
+		&i.DutchFloorPrice,
+		&i.DutchPriceStep,
+		&i.DutchStepIntervalSeconds,
+		&i.AuctionStart,
+		&i.Currency,
+		&i.SearchVector,
+		&i.CategoryID,
+		&i.Status,
+		&i.BidCount,
 	)
 	return i, err
 }
 
-const updateProduct = `-- name: UpdateProduct :exec
+const getProductByIdForUpdate = `-- name: GetProductByIdForUpdate :one
+SELECT id, seller_id, product_name, description, baseprice, auction_end, created_at, updated_at, soft_close_window_minutes, soft_close_extension_minutes, increment_type, increment_value, reserve_price, buy_now_price, auction_type, dutch_start_price, dutch_floor_price, dutch_price_step, dutch_step_interval_seconds, auction_start, currency, search_vector, category_id, status, bid_count FROM products
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) GetProductByIdForUpdate(ctx context.Context, id uuid.UUID) (Product, error) {
+	row := q.db.QueryRow(ctx, getProductByIdForUpdate, id)
+	var i Product
+	err := row.Scan(
+		&i.ID,
+		&i.SellerID,
+		&i.ProductName,
+		&i.Description,
+		&i.Baseprice,
+		&i.AuctionEnd,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.SoftCloseWindowMinutes,
+		&i.SoftCloseExtensionMinutes,
+		&i.IncrementType,
+		&i.IncrementValue,
+		&i.ReservePrice,
+		&i.BuyNowPrice,
+		&i.AuctionType,
+		&i.DutchStartPrice,
+		&i.DutchFloorPrice,
+		&i.DutchPriceStep,
+		&i.DutchStepIntervalSeconds,
+		&i.AuctionStart,
+		&i.Currency,
+		&i.SearchVector,
+		&i.CategoryID,
+		&i.Status,
+		&i.BidCount,
+	)
+	return i, err
+}
+
+const listActiveAuctions = `-- name: ListActiveAuctions :many
+SELECT id, seller_id, product_name, description, baseprice, auction_end, created_at, updated_at, soft_close_window_minutes, soft_close_extension_minutes, increment_type, increment_value, reserve_price, buy_now_price, auction_type, dutch_start_price, dutch_floor_price, dutch_price_step, dutch_step_interval_seconds, auction_start, currency, search_vector, category_id, status, bid_count FROM products
+WHERE status IN ('scheduled', 'live') AND auction_end > now()
+ORDER BY auction_end
+`
+
+func (q *Queries) ListActiveAuctions(ctx context.Context) ([]Product, error) {
+	rows, err := q.db.Query(ctx, listActiveAuctions)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Product
+	for rows.Next() {
+		var i Product
+		if err := rows.Scan(
+			&i.ID,
+			&i.SellerID,
+			&i.ProductName,
+			&i.Description,
+			&i.Baseprice,
+			&i.AuctionEnd,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.SoftCloseWindowMinutes,
+			&i.SoftCloseExtensionMinutes,
+			&i.IncrementType,
+			&i.IncrementValue,
+			&i.ReservePrice,
+			&i.BuyNowPrice,
+			&i.AuctionType,
+			&i.DutchStartPrice,
+			&i.DutchFloorPrice,
+			&i.DutchPriceStep,
+			&i.DutchStepIntervalSeconds,
+			&i.AuctionStart,
+			&i.Currency,
+			&i.SearchVector,
+			&i.CategoryID,
+			&i.Status,
+			&i.BidCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOverdueAuctions = `-- name: ListOverdueAuctions :many
+SELECT id, seller_id, product_name, description, baseprice, auction_end, created_at, updated_at, soft_close_window_minutes, soft_close_extension_minutes, increment_type, increment_value, reserve_price, buy_now_price, auction_type, dutch_start_price, dutch_floor_price, dutch_price_step, dutch_step_interval_seconds, auction_start, currency, search_vector, category_id, status, bid_count FROM products
+WHERE status IN ('scheduled', 'live')
+    AND auction_end <= $1::timestamptz
+    AND id NOT IN (SELECT product_id FROM auction_results)
+ORDER BY auction_end
+LIMIT 100
+`
+
+func (q *Queries) ListOverdueAuctions(ctx context.Context, endedBefore time.Time) ([]Product, error) {
+	rows, err := q.db.Query(ctx, listOverdueAuctions, endedBefore)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Product
+	for rows.Next() {
+		var i Product
+		if err := rows.Scan(
+			&i.ID,
+			&i.SellerID,
+			&i.ProductName,
+			&i.Description,
+			&i.Baseprice,
+			&i.AuctionEnd,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.SoftCloseWindowMinutes,
+			&i.SoftCloseExtensionMinutes,
+			&i.IncrementType,
+			&i.IncrementValue,
+			&i.ReservePrice,
+			&i.BuyNowPrice,
+			&i.AuctionType,
+			&i.DutchStartPrice,
+			&i.DutchFloorPrice,
+			&i.DutchPriceStep,
+			&i.DutchStepIntervalSeconds,
+			&i.AuctionStart,
+			&i.Currency,
+			&i.SearchVector,
+			&i.CategoryID,
+			&i.Status,
+			&i.BidCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listProductsEndingSoon = `-- name: ListProductsEndingSoon :many
+WITH RECURSIVE category_tree AS (
+    SELECT id FROM categories WHERE slug = $3::text
+    UNION ALL
+    SELECT c.id FROM categories c
+    JOIN category_tree t ON c.parent_id = t.id
+)
+SELECT id, seller_id, product_name, description, baseprice, buy_now_price, auction_type, auction_start, auction_end, currency, created_at, category_id, search_vector, bid_count, status, current_price, tags, cover_thumbnail_key FROM product_catalogue
+WHERE ($1::text IS NULL
+        OR search_vector @@ websearch_to_tsquery('english', $1::text))
+    AND ($2::uuid IS NULL OR seller_id = $2::uuid)
+    AND ($3::text IS NULL OR category_id IN (SELECT id FROM category_tree))
+    AND ($4::text IS NULL OR EXISTS (
+        SELECT 1 FROM product_tags pt
+        JOIN tags t ON t.id = pt.tag_id
+        WHERE pt.product_id = product_catalogue.id AND t.name = $4::text))
+    AND ($5::text IS NULL OR currency = $5::text)
+    AND ($6::timestamptz IS NULL OR auction_end < $6::timestamptz)
+    -- Without a status filter the catalogue shows auctions that are still
+    -- open; finished ones are found by asking for their status.
+    AND (($7::text IS NULL AND status IN ('scheduled', 'live')) OR status = $7::text)
+    AND ($8::bigint IS NULL OR current_price >= $8::bigint)
+    AND ($9::bigint IS NULL OR current_price <= $9::bigint)
+    AND ($10::bigint IS NULL
+        OR (auction_end, id) > (timestamptz 'epoch' + $10::bigint * interval '1 microsecond', $11::uuid))
+ORDER BY auction_end, id
+LIMIT $12
+`
+
+type ListProductsEndingSoonParams struct {
+	Search       pgtype.Text        `json:"search"`
+	SellerID     pgtype.UUID        `json:"seller_id"`
+	Category     pgtype.Text        `json:"category"`
+	Tag          pgtype.Text        `json:"tag"`
+	Currency     pgtype.Text        `json:"currency"`
+	EndingBefore pgtype.Timestamptz `json:"ending_before"`
+	Status       pgtype.Text        `json:"status"`
+	MinPrice     pgtype.Int8        `json:"min_price"`
+	MaxPrice     pgtype.Int8        `json:"max_price"`
+	AfterKey     pgtype.Int8        `json:"after_key"`
+	AfterID      pgtype.UUID        `json:"after_id"`
+	PageSize     int32              `json:"page_size"`
+}
+
+// The catalogue queries differ only in their order. Each pages on the columns
+// it sorts by, with after_key holding the last product's value: microseconds
+// since the epoch for times, and the bid count for most_bids.
+func (q *Queries) ListProductsEndingSoon(ctx context.Context, arg ListProductsEndingSoonParams) ([]ProductCatalogue, error) {
+	rows, err := q.db.Query(ctx, listProductsEndingSoon,
+		arg.Search,
+		arg.SellerID,
+		arg.Category,
+		arg.Tag,
+		arg.Currency,
+		arg.EndingBefore,
+		arg.Status,
+		arg.MinPrice,
+		arg.MaxPrice,
+		arg.AfterKey,
+		arg.AfterID,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProductCatalogue
+	for rows.Next() {
+		var i ProductCatalogue
+		if err := rows.Scan(
+			&i.ID,
+			&i.SellerID,
+			&i.ProductName,
+			&i.Description,
+			&i.Baseprice,
+			&i.BuyNowPrice,
+			&i.AuctionType,
+			&i.AuctionStart,
+			&i.AuctionEnd,
+			&i.Currency,
+			&i.CreatedAt,
+			&i.CategoryID,
+			&i.SearchVector,
+			&i.BidCount,
+			&i.Status,
+			&i.CurrentPrice,
+			&i.Tags,
+			&i.CoverThumbnailKey,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listProductsMostBids = `-- name: ListProductsMostBids :many
+WITH RECURSIVE category_tree AS (
+    SELECT id FROM categories WHERE slug = $3::text
+    UNION ALL
+    SELECT c.id FROM categories c
+    JOIN category_tree t ON c.parent_id = t.id
+)
+SELECT id, seller_id, product_name, description, baseprice, buy_now_price, auction_type, auction_start, auction_end, currency, created_at, category_id, search_vector, bid_count, status, current_price, tags, cover_thumbnail_key FROM product_catalogue
+WHERE ($1::text IS NULL
+        OR search_vector @@ websearch_to_tsquery('english', $1::text))
+    AND ($2::uuid IS NULL OR seller_id = $2::uuid)
+    AND ($3::text IS NULL OR category_id IN (SELECT id FROM category_tree))
+    AND ($4::text IS NULL OR EXISTS (
+        SELECT 1 FROM product_tags pt
+        JOIN tags t ON t.id = pt.tag_id
+        WHERE pt.product_id = product_catalogue.id AND t.name = $4::text))
+    AND ($5::text IS NULL OR currency = $5::text)
+    AND ($6::timestamptz IS NULL OR auction_end < $6::timestamptz)
+    -- Without a status filter the catalogue shows auctions that are still
+    -- open; finished ones are found by asking for their status.
+    AND (($7::text IS NULL AND status IN ('scheduled', 'live')) OR status = $7::text)
+    AND ($8::bigint IS NULL OR current_price >= $8::bigint)
+    AND ($9::bigint IS NULL OR current_price <= $9::bigint)
+    AND ($10::bigint IS NULL
+        OR (bid_count, id) < ($10::bigint, $11::uuid))
+ORDER BY bid_count DESC, id DESC
+LIMIT $12
+`
+
+type ListProductsMostBidsParams struct {
+	Search       pgtype.Text        `json:"search"`
+	SellerID     pgtype.UUID        `json:"seller_id"`
+	Category     pgtype.Text        `json:"category"`
+	Tag          pgtype.Text        `json:"tag"`
+	Currency     pgtype.Text        `json:"currency"`
+	EndingBefore pgtype.Timestamptz `json:"ending_before"`
+	Status       pgtype.Text        `json:"status"`
+	MinPrice     pgtype.Int8        `json:"min_price"`
+	MaxPrice     pgtype.Int8        `json:"max_price"`
+	AfterKey     pgtype.Int8        `json:"after_key"`
+	AfterID      pgtype.UUID        `json:"after_id"`
+	PageSize     int32              `json:"page_size"`
+}
+
+func (q *Queries) ListProductsMostBids(ctx context.Context, arg ListProductsMostBidsParams) ([]ProductCatalogue, error) {
+	rows, err := q.db.Query(ctx, listProductsMostBids,
+		arg.Search,
+		arg.SellerID,
+		arg.Category,
+		arg.Tag,
+		arg.Currency,
+		arg.EndingBefore,
+		arg.Status,
+		arg.MinPrice,
+		arg.MaxPrice,
+		arg.AfterKey,
+		arg.AfterID,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProductCatalogue
+	for rows.Next() {
+		var i ProductCatalogue
+		if err := rows.Scan(
+			&i.ID,
+			&i.SellerID,
+			&i.ProductName,
+			&i.Description,
+			&i.Baseprice,
+			&i.BuyNowPrice,
+			&i.AuctionType,
+			&i.AuctionStart,
+			&i.AuctionEnd,
+			&i.Currency,
+			&i.CreatedAt,
+			&i.CategoryID,
+			&i.SearchVector,
+			&i.BidCount,
+			&i.Status,
+			&i.CurrentPrice,
+			&i.Tags,
+			&i.CoverThumbnailKey,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listProductsNewest = `-- name: ListProductsNewest :many
+WITH RECURSIVE category_tree AS (
+    SELECT id FROM categories WHERE slug = $3::text
+    UNION ALL
+    SELECT c.id FROM categories c
+    JOIN category_tree t ON c.parent_id = t.id
+)
+SELECT id, seller_id, product_name, description, baseprice, buy_now_price, auction_type, auction_start, auction_end, currency, created_at, category_id, search_vector, bid_count, status, current_price, tags, cover_thumbnail_key FROM product_catalogue
+WHERE ($1::text IS NULL
+        OR search_vector @@ websearch_to_tsquery('english', $1::text))
+    AND ($2::uuid IS NULL OR seller_id = $2::uuid)
+    AND ($3::text IS NULL OR category_id IN (SELECT id FROM category_tree))
+    AND ($4::text IS NULL OR EXISTS (
+        SELECT 1 FROM product_tags pt
+        JOIN tags t ON t.id = pt.tag_id
+        WHERE pt.product_id = product_catalogue.id AND t.name = $4::text))
+    AND ($5::text IS NULL OR currency = $5::text)
+    AND ($6::timestamptz IS NULL OR auction_end < $6::timestamptz)
+    -- Without a status filter the catalogue shows auctions that are still
+    -- open; finished ones are found by asking for their status.
+    AND (($7::text IS NULL AND status IN ('scheduled', 'live')) OR status = $7::text)
+    AND ($8::bigint IS NULL OR current_price >= $8::bigint)
+    AND ($9::bigint IS NULL OR current_price <= $9::bigint)
+    AND ($10::bigint IS NULL
+        OR (created_at, id) < (timestamptz 'epoch' + $10::bigint * interval '1 microsecond', $11::uuid))
+ORDER BY created_at DESC, id DESC
+LIMIT $12
+`
+
+type ListProductsNewestParams struct {
+	Search       pgtype.Text        `json:"search"`
+	SellerID     pgtype.UUID        `json:"seller_id"`
+	Category     pgtype.Text        `json:"category"`
+	Tag          pgtype.Text        `json:"tag"`
+	Currency     pgtype.Text        `json:"currency"`
+	EndingBefore pgtype.Timestamptz `json:"ending_before"`
+	Status       pgtype.Text        `json:"status"`
+	MinPrice     pgtype.Int8        `json:"min_price"`
+	MaxPrice     pgtype.Int8        `json:"max_price"`
+	AfterKey     pgtype.Int8        `json:"after_key"`
+	AfterID      pgtype.UUID        `json:"after_id"`
+	PageSize     int32              `json:"page_size"`
+}
+
+func (q *Queries) ListProductsNewest(ctx context.Context, arg ListProductsNewestParams) ([]ProductCatalogue, error) {
+	rows, err := q.db.Query(ctx, listProductsNewest,
+		arg.Search,
+		arg.SellerID,
+		arg.Category,
+		arg.Tag,
+		arg.Currency,
+		arg.EndingBefore,
+		arg.Status,
+		arg.MinPrice,
+		arg.MaxPrice,
+		arg.AfterKey,
+		arg.AfterID,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProductCatalogue
+	for rows.Next() {
+		var i ProductCatalogue
+		if err := rows.Scan(
+			&i.ID,
+			&i.SellerID,
+			&i.ProductName,
+			&i.Description,
+			&i.Baseprice,
+			&i.BuyNowPrice,
+			&i.AuctionType,
+			&i.AuctionStart,
+			&i.AuctionEnd,
+			&i.Currency,
+			&i.CreatedAt,
+			&i.CategoryID,
+			&i.SearchVector,
+			&i.BidCount,
+			&i.Status,
+			&i.CurrentPrice,
+			&i.Tags,
+			&i.CoverThumbnailKey,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setProductStatus = `-- name: SetProductStatus :execrows
+UPDATE products
+SET status = $1, updated_at = now()
+WHERE id = $2 AND status = $3
+`
+
+type SetProductStatusParams struct {
+	ToStatus   string    `json:"to_status"`
+	ID         uuid.UUID `json:"id"`
+	FromStatus string    `json:"from_status"`
+}
+
+func (q *Queries) SetProductStatus(ctx context.Context, arg SetProductStatusParams) (int64, error) {
+	result, err := q.db.Exec(ctx, setProductStatus, arg.ToStatus, arg.ID, arg.FromStatus)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updateProduct = `-- name: UpdateProduct :execrows
 UPDATE products
 SET
     product_name = COALESCE($3, product_name),
     description = COALESCE($4, description),
     baseprice = COALESCE($5, baseprice),
-    auction_end = COALESCE($6, auction_end)
+    auction_end = COALESCE($6, auction_end),
+    reserve_price = COALESCE($7, reserve_price),
+    buy_now_price = COALESCE($8, buy_now_price),
+    category_id = COALESCE($9, category_id),
+    updated_at = now()
 WHERE id = $1 AND seller_id = $2
 `
 
 type UpdateProductParams struct {
-	ID          uuid.UUID `json:"id"`
-	SellerID    uuid.UUID `json:"seller_id"`
-	ProductName string    `json:"product_name"`
-	Description string    `json:"description"`
-	Baseprice   float64   `json:"baseprice"`
-	AuctionEnd  time.Time `json:"auction_end"`
+	ID           uuid.UUID          `json:"id"`
+	SellerID     uuid.UUID          `json:"seller_id"`
+	ProductName  pgtype.Text        `json:"product_name"`
+	Description  pgtype.Text        `json:"description"`
+	Baseprice    pgtype.Int8        `json:"baseprice"`
+	AuctionEnd   pgtype.Timestamptz `json:"auction_end"`
+	ReservePrice pgtype.Int8        `json:"reserve_price"`
+	BuyNowPrice  pgtype.Int8        `json:"buy_now_price"`
+	CategoryID   pgtype.UUID        `json:"category_id"`
 }
 
-func (q *Queries) UpdateProduct(ctx context.Context, arg UpdateProductParams) error {
-	_, err := q.db.Exec(ctx, updateProduct,
+func (q *Queries) UpdateProduct(ctx context.Context, arg UpdateProductParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateProduct,
 		arg.ID,
 		arg.SellerID,
 		arg.ProductName,
 		arg.Description,
 		arg.Baseprice,
 		arg.AuctionEnd,
+		arg.ReservePrice,
+		arg.BuyNowPrice,
+		arg.CategoryID,
 	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updateProductAuctionEnd = `-- name: UpdateProductAuctionEnd :exec
+UPDATE products
+SET auction_end = $2, updated_at = now()
+WHERE id = $1
+`
+
+type UpdateProductAuctionEndParams struct {
+	ID         uuid.UUID `json:"id"`
+	AuctionEnd time.Time `json:"auction_end"`
+}
+
+func (q *Queries) UpdateProductAuctionEnd(ctx context.Context, arg UpdateProductAuctionEndParams) error {
+	_, err := q.db.Exec(ctx, updateProductAuctionEnd, arg.ID, arg.AuctionEnd)
 	return err
 }
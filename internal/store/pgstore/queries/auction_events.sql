@@ -0,0 +1,8 @@
+-- name: NotifyAuctionEvent :exec
+SELECT pg_notify('auction_events', sqlc.arg(payload)::text);
+
+-- name: TryLockAuction :one
+SELECT pg_try_advisory_lock(hashtextextended(sqlc.arg(product_id)::uuid::text, 0));
+
+-- name: UnlockAuction :one
+SELECT pg_advisory_unlock(hashtextextended(sqlc.arg(product_id)::uuid::text, 0));
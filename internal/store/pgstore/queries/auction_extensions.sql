@@ -0,0 +1,9 @@
+-- name: CreateAuctionExtension :exec
+INSERT INTO auction_extensions (
+    product_id, bid_id, previous_end, new_end
+) VALUES ($1, $2, $3, $4);
+
+-- name: ListAuctionExtensionsByProductId :many
+SELECT * FROM auction_extensions
+WHERE product_id = $1
+ORDER BY created_at;
@@ -0,0 +1,9 @@
+-- name: CreateAuctionResult :one
+INSERT INTO auction_results (
+    product_id, winner_id, bid_id, hammer_price, sold_via
+) VALUES ($1, $2, $3, $4, $5)
+    RETURNING *;
+
+-- name: GetAuctionResultByProductId :one
+SELECT * FROM auction_results
+WHERE product_id = $1;
@@ -1,4 +1,7 @@
 -- name: CreateBid :one
+WITH counted AS (
+    UPDATE products SET bid_count = bid_count + 1 WHERE id = $1
+)
 INSERT INTO bids (
     product_id, bidder_id, bid_amount
 ) VALUES ($1, $2, $3)
@@ -7,10 +10,33 @@ INSERT INTO bids (
 -- name: GetBidsByProductId :many
 SELECT * FROM bids
 WHERE product_id = $1
-ORDER BY bid_amount DESC;
+ORDER BY bid_amount DESC, seq ASC;
 
 -- name: GetHighestBidByProductId :one
 SELECT * FROM bids
 WHERE product_id = $1
-ORDER BY bid_amount DESC
+ORDER BY bid_amount DESC, seq ASC
     LIMIT 1;
+
+-- name: GetBidByProductAndBidder :one
+SELECT * FROM bids
+WHERE product_id = $1 AND bidder_id = $2;
+
+-- name: UpdateBidAmount :one
+UPDATE bids
+SET bid_amount = $2, created_at = now(), seq = nextval('bids_seq_seq')
+WHERE id = $1
+    RETURNING *;
+
+-- name: CountBidsByProductId :one
+SELECT count(*) FROM bids
+WHERE product_id = $1;
+
+-- name: ListBidsByProductId :many
+SELECT bids.*, users.user_name AS bidder_name FROM bids
+JOIN users ON users.id = bids.bidder_id
+WHERE bids.product_id = sqlc.arg(product_id)
+  AND (sqlc.narg(bidder_id)::uuid IS NULL OR bids.bidder_id = sqlc.narg(bidder_id))
+  AND (sqlc.narg(before_seq)::bigint IS NULL OR bids.seq < sqlc.narg(before_seq))
+ORDER BY bids.seq DESC
+LIMIT sqlc.arg(page_size);
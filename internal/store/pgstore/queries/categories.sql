@@ -0,0 +1,44 @@
+-- name: CreateCategory :one
+INSERT INTO categories (parent_id, name, slug)
+VALUES (sqlc.narg('parent_id'), sqlc.arg('name'), sqlc.arg('slug'))
+RETURNING *;
+
+-- name: GetCategoryById :one
+SELECT * FROM categories
+WHERE id = $1;
+
+-- name: ListCategories :many
+SELECT * FROM categories
+ORDER BY name;
+
+-- name: UpdateCategory :one
+UPDATE categories
+SET
+    name = COALESCE(sqlc.narg('name'), name),
+    slug = COALESCE(sqlc.narg('slug'), slug),
+    parent_id = CASE WHEN sqlc.arg('move')::bool THEN sqlc.narg('parent_id') ELSE parent_id END,
+    updated_at = now()
+WHERE id = sqlc.arg('id')
+RETURNING *;
+
+-- name: DeleteCategory :execrows
+DELETE FROM categories
+WHERE id = $1;
+
+-- name: IsCategoryInSubtree :one
+WITH RECURSIVE subtree AS (
+    SELECT id FROM categories WHERE id = sqlc.arg('root_id')::uuid
+    UNION ALL
+    SELECT c.id FROM categories c
+    JOIN subtree s ON c.parent_id = s.id
+)
+SELECT EXISTS (SELECT 1 FROM subtree WHERE id = sqlc.arg('category_id')::uuid);
+
+-- name: SubscribeToCategory :exec
+INSERT INTO category_subscriptions (user_id, category_id)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
+
+-- name: UnsubscribeFromCategory :exec
+DELETE FROM category_subscriptions
+WHERE user_id = $1 AND category_id = $2;
@@ -0,0 +1,27 @@
+-- name: CreateIdempotencyKey :execrows
+INSERT INTO idempotency_keys (
+    user_id, key, request_hash, locked_until
+) VALUES ($1, $2, $3, $4)
+ON CONFLICT (user_id, key) DO NOTHING;
+
+-- name: GetIdempotencyKey :one
+SELECT * FROM idempotency_keys
+WHERE user_id = $1 AND key = $2;
+
+-- name: TakeOverIdempotencyKey :execrows
+UPDATE idempotency_keys
+SET locked_until = sqlc.arg(locked_until)
+WHERE user_id = $1 AND key = $2 AND status_code IS NULL AND locked_until < sqlc.arg(now);
+
+-- name: CompleteIdempotencyKey :exec
+UPDATE idempotency_keys
+SET status_code = $3, response_body = $4, completed_at = now()
+WHERE user_id = $1 AND key = $2;
+
+-- name: DeleteIdempotencyKey :exec
+DELETE FROM idempotency_keys
+WHERE user_id = $1 AND key = $2;
+
+-- name: DeleteExpiredIdempotencyKey :exec
+DELETE FROM idempotency_keys
+WHERE user_id = $1 AND key = $2 AND created_at < sqlc.arg(expired_before);
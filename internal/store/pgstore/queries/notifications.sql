@@ -0,0 +1,17 @@
+-- name: CreateNotifications :execrows
+INSERT INTO notifications (user_id, product_id, kind)
+SELECT unnest(sqlc.arg('user_ids')::uuid[]), sqlc.arg('product_id'), sqlc.arg('kind')
+ON CONFLICT DO NOTHING;
+
+-- name: ListNotificationsByUserId :many
+SELECT n.id, n.product_id, n.kind, n.created_at, n.read_at, p.product_name
+FROM notifications n
+JOIN products p ON p.id = n.product_id
+WHERE n.user_id = $1
+ORDER BY n.created_at DESC, n.id DESC
+LIMIT $2;
+
+-- name: MarkNotificationsRead :exec
+UPDATE notifications
+SET read_at = now()
+WHERE user_id = $1 AND read_at IS NULL;
@@ -0,0 +1,28 @@
+-- name: CreateProductImage :one
+INSERT INTO product_images (
+    id, product_id, position, content_type, original_key, thumbnail_key, width, height, size_bytes
+) VALUES (
+    $1, $2,
+    (SELECT COALESCE(max(position) + 1, 0) FROM product_images WHERE product_id = $2),
+    $3, $4, $5, $6, $7, $8
+)
+RETURNING *;
+
+-- name: CountProductImages :one
+SELECT count(*) FROM product_images
+WHERE product_id = $1;
+
+-- name: ListProductImages :many
+SELECT * FROM product_images
+WHERE product_id = $1
+ORDER BY position;
+
+-- name: DeleteProductImage :one
+DELETE FROM product_images
+WHERE id = $1 AND product_id = $2
+RETURNING *;
+
+-- name: ReorderProductImages :exec
+UPDATE product_images
+SET position = array_position(sqlc.arg('image_ids')::uuid[], id) - 1
+WHERE product_id = sqlc.arg('product_id');
@@ -0,0 +1,8 @@
+-- name: CreateProductStatusTransition :exec
+INSERT INTO product_status_transitions (product_id, from_status, to_status, actor_id, created_at)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: ListProductStatusTransitions :many
+SELECT * FROM product_status_transitions
+WHERE product_id = $1
+ORDER BY created_at, id;
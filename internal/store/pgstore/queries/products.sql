@@ -1,16 +1,24 @@
 -- name: CreatedProduct :one
 INSERT INTO products (
-    product_name, seller_id, description, baseprice, auction_end
-) VALUES ($1, $2, $3, $4, $5)
+    product_name, seller_id, description, baseprice, auction_end,
+    soft_close_window_minutes, soft_close_extension_minutes,
+    increment_type, increment_value, reserve_price, buy_now_price,
+    auction_type, dutch_start_price, dutch_floor_price, dutch_price_step,
+    dutch_step_interval_seconds, auction_start, currency, category_id, status
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)
 RETURNING id;
 
--- name: UpdateProduct :exec
+-- name: UpdateProduct :execrows
 UPDATE products
 SET
-    product_name = COALESCE($3, product_name),
-    description = COALESCE($4, description),
-    baseprice = COALESCE($5, baseprice),
-    auction_end = COALESCE($6, auction_end)
+    product_name = COALESCE(sqlc.narg('product_name'), product_name),
+    description = COALESCE(sqlc.narg('description'), description),
+    baseprice = COALESCE(sqlc.narg('baseprice'), baseprice),
+    auction_end = COALESCE(sqlc.narg('auction_end'), auction_end),
+    reserve_price = COALESCE(sqlc.narg('reserve_price'), reserve_price),
+    buy_now_price = COALESCE(sqlc.narg('buy_now_price'), buy_now_price),
+    category_id = COALESCE(sqlc.narg('category_id'), category_id),
+    updated_at = now()
 WHERE id = $1 AND seller_id = $2;
 
 -- name: DeleteProduct :exec
@@ -20,3 +28,118 @@ WHERE id = $1 AND seller_id = $2;
 -- name: GetProductById :one
 SELECT * FROM products
 WHERE id = $1;
+
+-- name: GetProductByIdForUpdate :one
+SELECT * FROM products
+WHERE id = $1
+FOR UPDATE;
+
+-- name: ListActiveAuctions :many
+SELECT * FROM products
+WHERE status IN ('scheduled', 'live') AND auction_end > now()
+ORDER BY auction_end;
+
+-- name: ListOverdueAuctions :many
+SELECT * FROM products
+WHERE status IN ('scheduled', 'live')
+    AND auction_end <= sqlc.arg('ended_before')::timestamptz
+    AND id NOT IN (SELECT product_id FROM auction_results)
+ORDER BY auction_end
+LIMIT 100;
+
+-- name: SetProductStatus :execrows
+UPDATE products
+SET status = sqlc.arg('to_status'), updated_at = now()
+WHERE id = sqlc.arg('id') AND status = sqlc.arg('from_status');
+
+-- name: UpdateProductAuctionEnd :exec
+UPDATE products
+SET auction_end = $2, updated_at = now()
+WHERE id = $1;
+
+-- The catalogue queries differ only in their order. Each pages on the columns
+-- it sorts by, with after_key holding the last product's value: microseconds
+-- since the epoch for times, and the bid count for most_bids.
+-- name: ListProductsEndingSoon :many
+WITH RECURSIVE category_tree AS (
+    SELECT id FROM categories WHERE slug = sqlc.narg('category')::text
+    UNION ALL
+    SELECT c.id FROM categories c
+    JOIN category_tree t ON c.parent_id = t.id
+)
+SELECT * FROM product_catalogue
+WHERE (sqlc.narg('search')::text IS NULL
+        OR search_vector @@ websearch_to_tsquery('english', sqlc.narg('search')::text))
+    AND (sqlc.narg('seller_id')::uuid IS NULL OR seller_id = sqlc.narg('seller_id')::uuid)
+    AND (sqlc.narg('category')::text IS NULL OR category_id IN (SELECT id FROM category_tree))
+    AND (sqlc.narg('tag')::text IS NULL OR EXISTS (
+        SELECT 1 FROM product_tags pt
+        JOIN tags t ON t.id = pt.tag_id
+        WHERE pt.product_id = product_catalogue.id AND t.name = sqlc.narg('tag')::text))
+    AND (sqlc.narg('currency')::text IS NULL OR currency = sqlc.narg('currency')::text)
+    AND (sqlc.narg('ending_before')::timestamptz IS NULL OR auction_end < sqlc.narg('ending_before')::timestamptz)
+    -- Without a status filter the catalogue shows auctions that are still
+    -- open; finished ones are found by asking for their status.
+    AND ((sqlc.narg('status')::text IS NULL AND status IN ('scheduled', 'live')) OR status = sqlc.narg('status')::text)
+    AND (sqlc.narg('min_price')::bigint IS NULL OR current_price >= sqlc.narg('min_price')::bigint)
+    AND (sqlc.narg('max_price')::bigint IS NULL OR current_price <= sqlc.narg('max_price')::bigint)
+    AND (sqlc.narg('after_key')::bigint IS NULL
+        OR (auction_end, id) > (timestamptz 'epoch' + sqlc.narg('after_key')::bigint * interval '1 microsecond', sqlc.narg('after_id')::uuid))
+ORDER BY auction_end, id
+LIMIT sqlc.arg('page_size');
+
+-- name: ListProductsNewest :many
+WITH RECURSIVE category_tree AS (
+    SELECT id FROM categories WHERE slug = sqlc.narg('category')::text
+    UNION ALL
+    SELECT c.id FROM categories c
+    JOIN category_tree t ON c.parent_id = t.id
+)
+SELECT * FROM product_catalogue
+WHERE (sqlc.narg('search')::text IS NULL
+        OR search_vector @@ websearch_to_tsquery('english', sqlc.narg('search')::text))
+    AND (sqlc.narg('seller_id')::uuid IS NULL OR seller_id = sqlc.narg('seller_id')::uuid)
+    AND (sqlc.narg('category')::text IS NULL OR category_id IN (SELECT id FROM category_tree))
+    AND (sqlc.narg('tag')::text IS NULL OR EXISTS (
+        SELECT 1 FROM product_tags pt
+        JOIN tags t ON t.id = pt.tag_id
+        WHERE pt.product_id = product_catalogue.id AND t.name = sqlc.narg('tag')::text))
+    AND (sqlc.narg('currency')::text IS NULL OR currency = sqlc.narg('currency')::text)
+    AND (sqlc.narg('ending_before')::timestamptz IS NULL OR auction_end < sqlc.narg('ending_before')::timestamptz)
+    -- Without a status filter the catalogue shows auctions that are still
+    -- open; finished ones are found by asking for their status.
+    AND ((sqlc.narg('status')::text IS NULL AND status IN ('scheduled', 'live')) OR status = sqlc.narg('status')::text)
+    AND (sqlc.narg('min_price')::bigint IS NULL OR current_price >= sqlc.narg('min_price')::bigint)
+    AND (sqlc.narg('max_price')::bigint IS NULL OR current_price <= sqlc.narg('max_price')::bigint)
+    AND (sqlc.narg('after_key')::bigint IS NULL
+        OR (created_at, id) < (timestamptz 'epoch' + sqlc.narg('after_key')::bigint * interval '1 microsecond', sqlc.narg('after_id')::uuid))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg('page_size');
+
+-- name: ListProductsMostBids :many
+WITH RECURSIVE category_tree AS (
+    SELECT id FROM categories WHERE slug = sqlc.narg('category')::text
+    UNION ALL
+    SELECT c.id FROM categories c
+    JOIN category_tree t ON c.parent_id = t.id
+)
+SELECT * FROM product_catalogue
+WHERE (sqlc.narg('search')::text IS NULL
+        OR search_vector @@ websearch_to_tsquery('english', sqlc.narg('search')::text))
+    AND (sqlc.narg('seller_id')::uuid IS NULL OR seller_id = sqlc.narg('seller_id')::uuid)
+    AND (sqlc.narg('category')::text IS NULL OR category_id IN (SELECT id FROM category_tree))
+    AND (sqlc.narg('tag')::text IS NULL OR EXISTS (
+        SELECT 1 FROM product_tags pt
+        JOIN tags t ON t.id = pt.tag_id
+        WHERE pt.product_id = product_catalogue.id AND t.name = sqlc.narg('tag')::text))
+    AND (sqlc.narg('currency')::text IS NULL OR currency = sqlc.narg('currency')::text)
+    AND (sqlc.narg('ending_before')::timestamptz IS NULL OR auction_end < sqlc.narg('ending_before')::timestamptz)
+    -- Without a status filter the catalogue shows auctions that are still
+    -- open; finished ones are found by asking for their status.
+    AND ((sqlc.narg('status')::text IS NULL AND status IN ('scheduled', 'live')) OR status = sqlc.narg('status')::text)
+    AND (sqlc.narg('min_price')::bigint IS NULL OR current_price >= sqlc.narg('min_price')::bigint)
+    AND (sqlc.narg('max_price')::bigint IS NULL OR current_price <= sqlc.narg('max_price')::bigint)
+    AND (sqlc.narg('after_key')::bigint IS NULL
+        OR (bid_count, id) < (sqlc.narg('after_key')::bigint, sqlc.narg('after_id')::uuid))
+ORDER BY bid_count DESC, id DESC
+LIMIT sqlc.arg('page_size');
@@ -0,0 +1,66 @@
+-- name: CreateTags :exec
+INSERT INTO tags (name)
+SELECT unnest(sqlc.arg('names')::text[])
+ON CONFLICT (name) DO NOTHING;
+
+-- name: ListTags :many
+SELECT t.id, t.name, t.created_at, count(pt.product_id) AS product_count
+FROM tags t
+LEFT JOIN product_tags pt ON pt.tag_id = t.id
+GROUP BY t.id
+ORDER BY t.name;
+
+-- name: GetTagByName :one
+SELECT * FROM tags
+WHERE name = $1;
+
+-- name: RenameTag :one
+UPDATE tags
+SET name = sqlc.arg('name')
+WHERE id = sqlc.arg('id')
+RETURNING *;
+
+-- name: DeleteTag :execrows
+DELETE FROM tags
+WHERE id = $1;
+
+-- name: ClearProductTags :exec
+DELETE FROM product_tags
+WHERE product_id = $1;
+
+-- name: AddProductTags :exec
+INSERT INTO product_tags (product_id, tag_id)
+SELECT sqlc.arg('product_id'), id FROM tags
+WHERE name = ANY(sqlc.arg('names')::text[])
+ON CONFLICT DO NOTHING;
+
+-- name: ListTagNamesByProductId :many
+SELECT t.name
+FROM product_tags pt
+JOIN tags t ON t.id = pt.tag_id
+WHERE pt.product_id = $1
+ORDER BY t.name;
+
+-- name: SubscribeToTag :exec
+INSERT INTO tag_subscriptions (user_id, tag_id)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
+
+-- name: UnsubscribeFromTag :exec
+DELETE FROM tag_subscriptions
+WHERE user_id = $1 AND tag_id = $2;
+
+-- name: ListSubscribersForProduct :many
+WITH RECURSIVE ancestors AS (
+    SELECT id, parent_id FROM categories
+    WHERE id = (SELECT category_id FROM products WHERE products.id = sqlc.arg('product_id'))
+    UNION ALL
+    SELECT c.id, c.parent_id FROM categories c
+    JOIN ancestors a ON c.id = a.parent_id
+)
+SELECT cs.user_id FROM category_subscriptions cs
+WHERE cs.category_id IN (SELECT ancestors.id FROM ancestors)
+UNION
+SELECT ts.user_id FROM tag_subscriptions ts
+JOIN product_tags pt ON pt.tag_id = ts.tag_id
+WHERE pt.product_id = sqlc.arg('product_id');
@@ -26,3 +26,7 @@ SELECT
     updated_at
 FROM users
 WHERE email = $1;
+
+-- name: IsUserAdmin :one
+SELECT is_admin FROM users
+WHERE id = $1;
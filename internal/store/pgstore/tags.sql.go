@@ -0,0 +1,230 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+// source: tags.sql
+
+package pgstore
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const addProductTags = `-- name: AddProductTags :exec
+INSERT INTO product_tags (product_id, tag_id)
+SELECT $1, id FROM tags
+WHERE name = ANY($2::text[])
+ON CONFLICT DO NOTHING
+`
+
+type AddProductTagsParams struct {
+	ProductID uuid.UUID `json:"product_id"`
+	Names     []string  `json:"names"`
+}
+
+func (q *Queries) AddProductTags(ctx context.Context, arg AddProductTagsParams) error {
+	_, err := q.db.Exec(ctx, addProductTags, arg.ProductID, arg.Names)
+	return err
+}
+
+const clearProductTags = `-- name: ClearProductTags :exec
+DELETE FROM product_tags
+WHERE product_id = $1
+`
+
+func (q *Queries) ClearProductTags(ctx context.Context, productID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, clearProductTags, productID)
+	return err
+}
+
+const createTags = `-- name: CreateTags :exec
+INSERT INTO tags (name)
+SELECT unnest($1::text[])
+ON CONFLICT (name) DO NOTHING
+`
+
+func (q *Queries) CreateTags(ctx context.Context, names []string) error {
+	_, err := q.db.Exec(ctx, createTags, names)
+	return err
+}
+
+const deleteTag = `-- name: DeleteTag :execrows
+DELETE FROM tags
+WHERE id = $1
+`
+
+func (q *Queries) DeleteTag(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteTag, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getTagByName = `-- name: GetTagByName :one
+SELECT id, name, created_at FROM tags
+WHERE name = $1
+`
+
+func (q *Queries) GetTagByName(ctx context.Context, name string) (Tag, error) {
+	row := q.db.QueryRow(ctx, getTagByName, name)
+	var i Tag
+	err := row.Scan(&i.ID, &i.Name, &i.CreatedAt)
+	return i, err
+}
+
+const listSubscribersForProduct = `-- name: ListSubscribersForProduct :many
+WITH RECURSIVE ancestors AS (
+    SELECT id, parent_id FROM categories
+    WHERE id = (SELECT category_id FROM products WHERE products.id = $1)
+    UNION ALL
+    SELECT c.id, c.parent_id FROM categories c
+    JOIN ancestors a ON c.id = a.parent_id
+)
+SELECT cs.user_id FROM category_subscriptions cs
+WHERE cs.category_id IN (SELECT ancestors.id FROM ancestors)
+UNION
+SELECT ts.user_id FROM tag_subscriptions ts
+JOIN product_tags pt ON pt.tag_id = ts.tag_id
+WHERE pt.product_id = $1
+`
+
+func (q *Queries) ListSubscribersForProduct(ctx context.Context, productID uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := q.db.Query(ctx, listSubscribersForProduct, productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var user_id uuid.UUID
+		if err := rows.Scan(&user_id); err != nil {
+			return nil, err
+		}
+		items = append(items, user_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTagNamesByProductId = `-- name: ListTagNamesByProductId :many
+SELECT t.name
+FROM product_tags pt
+JOIN tags t ON t.id = pt.tag_id
+WHERE pt.product_id = $1
+ORDER BY t.name
+`
+
+func (q *Queries) ListTagNamesByProductId(ctx context.Context, productID uuid.UUID) ([]string, error) {
+	rows, err := q.db.Query(ctx, listTagNamesByProductId, productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		items = append(items, name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTags = `-- name: ListTags :many
+SELECT t.id, t.name, t.created_at, count(pt.product_id) AS product_count
+FROM tags t
+LEFT JOIN product_tags pt ON pt.tag_id = t.id
+GROUP BY t.id
+ORDER BY t.name
+`
+
+type ListTagsRow struct {
+	ID           uuid.UUID `json:"id"`
+	Name         string    `json:"name"`
+	CreatedAt    time.Time `json:"created_at"`
+	ProductCount int64     `json:"product_count"`
+}
+
+func (q *Queries) ListTags(ctx context.Context) ([]ListTagsRow, error) {
+	rows, err := q.db.Query(ctx, listTags)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListTagsRow
+	for rows.Next() {
+		var i ListTagsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.CreatedAt,
+			&i.ProductCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const renameTag = `-- name: RenameTag :one
+UPDATE tags
+SET name = $1
+WHERE id = $2
+RETURNING id, name, created_at
+`
+
+type RenameTagParams struct {
+	Name string    `json:"name"`
+	ID   uuid.UUID `json:"id"`
+}
+
+func (q *Queries) RenameTag(ctx context.Context, arg RenameTagParams) (Tag, error) {
+	row := q.db.QueryRow(ctx, renameTag, arg.Name, arg.ID)
+	var i Tag
+	err := row.Scan(&i.ID, &i.Name, &i.CreatedAt)
+	return i, err
+}
+
+const subscribeToTag = `-- name: SubscribeToTag :exec
+INSERT INTO tag_subscriptions (user_id, tag_id)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING
+`
+
+type SubscribeToTagParams struct {
+	UserID uuid.UUID `json:"user_id"`
+	TagID  uuid.UUID `json:"tag_id"`
+}
+
+func (q *Queries) SubscribeToTag(ctx context.Context, arg SubscribeToTagParams) error {
+	_, err := q.db.Exec(ctx, subscribeToTag, arg.UserID, arg.TagID)
+	return err
+}
+
+const unsubscribeFromTag = `-- name: UnsubscribeFromTag :exec
+DELETE FROM tag_subscriptions
+WHERE user_id = $1 AND tag_id = $2
+`
+
+type UnsubscribeFromTagParams struct {
+	UserID uuid.UUID `json:"user_id"`
+	TagID  uuid.UUID `json:"tag_id"`
+}
+
+func (q *Queries) UnsubscribeFromTag(ctx context.Context, arg UnsubscribeFromTagParams) error {
+	_, err := q.db.Exec(ctx, unsubscribeFromTag, arg.UserID, arg.TagID)
+	return err
+}
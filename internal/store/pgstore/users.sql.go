@@ -112,3 +112,15 @@ func (q *Queries) GetUserById(ctx context.Context, id uuid.UUID) (GetUserByIdRow
 	)
 	return i, err
 }
+
+const isUserAdmin = `-- name: IsUserAdmin :one
+SELECT is_admin FROM users
+WHERE id = $1
+`
+
+func (q *Queries) IsUserAdmin(ctx context.Context, id uuid.UUID) (bool, error) {
+	row := q.db.QueryRow(ctx, isUserAdmin, id)
+	var is_admin bool
+	err := row.Scan(&is_admin)
+	return is_admin, err
+}
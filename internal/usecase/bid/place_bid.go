@@ -0,0 +1,18 @@
+package bid
+
+import (
+	"context"
+	"github.com/FelipePn10/Gobid/internal/services"
+	"github.com/FelipePn10/Gobid/internal/validator"
+)
+
+type PlaceBidReq struct {
+	Amount services.Money `json:"amount"`
+}
+
+func (req PlaceBidReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	eval.CheckField(req.Amount.Amount > 0, "amount", "the bid must be greater than zero")
+	return eval
+}
@@ -0,0 +1,18 @@
+package bid
+
+import (
+	"context"
+	"github.com/FelipePn10/Gobid/internal/services"
+	"github.com/FelipePn10/Gobid/internal/validator"
+)
+
+type PlaceMaxBidReq struct {
+	MaxAmount services.Money `json:"max_amount"`
+}
+
+func (req PlaceMaxBidReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	eval.CheckField(req.MaxAmount.Amount > 0, "max_amount", "the maximum bid must be greater than zero")
+	return eval
+}
@@ -2,27 +2,104 @@ package product
 
 import (
 	"context"
+	"errors"
+	"github.com/FelipePn10/Gobid/internal/services"
 	"github.com/FelipePn10/Gobid/internal/validator"
 	"github.com/google/uuid"
+	"strings"
 	"time"
 )
 
+// Monetary fields accept "12.50", 12.50 or {"amount": "12.50", "currency": "USD"}
+// and are kept in minor units; see Priced. A percentage increment_value is
+// read with two decimals, so "2.5" becomes 250 basis points.
 type CreateProductReq struct {
-	SellerID    uuid.UUID `json:"seller_id"`
-	ProductName string    `json:"product_name"`
-	Description string    `json:"description"`
-	Baseprice   float64   `json:"baseprice"`
-	AuctionEnd  time.Time `json:"auction_end"`
+	SellerID           uuid.UUID      `json:"seller_id"`
+	ProductName        string         `json:"product_name"`
+	Description        string         `json:"description"`
+	Currency           string         `json:"currency,omitempty"`
+	Baseprice          services.Money `json:"baseprice"`
+	AuctionStart       *time.Time     `json:"auction_start,omitempty"`
+	AuctionEnd         time.Time      `json:"auction_end"`
+	SoftCloseWindow    int32          `json:"soft_close_window_minutes"`
+	SoftCloseExtension int32          `json:"soft_close_extension_minutes"`
+	IncrementType      string         `json:"increment_type,omitempty"`
+	IncrementValue     services.Money `json:"increment_value,omitempty"`
+	ReservePrice       services.Money `json:"reserve_price,omitempty"`
+	BuyNowPrice        services.Money `json:"buy_now_price,omitempty"`
+	AuctionType        string         `json:"auction_type,omitempty"`
+	DutchStartPrice    services.Money `json:"dutch_start_price,omitempty"`
+	DutchFloorPrice    services.Money `json:"dutch_floor_price,omitempty"`
+	DutchPriceStep     services.Money `json:"dutch_price_step,omitempty"`
+	DutchStepInterval  int32          `json:"dutch_step_interval_seconds,omitempty"`
+	CategoryID         *uuid.UUID     `json:"category_id,omitempty"`
+	Tags               []string       `json:"tags,omitempty"`
+	Draft              bool           `json:"draft,omitempty"`
 }
 
 type UpdateProductReq struct {
-	ProductName *string    `json:"product_name,omitempty"`
-	Description *string    `json:"description,omitempty"`
-	Baseprice   *float64   `json:"base_price,omitempty"`
-	AuctionEnd  *time.Time `json:"auction_end,omitempty"`
+	ProductName  *string         `json:"product_name,omitempty"`
+	Description  *string         `json:"description,omitempty"`
+	Baseprice    *services.Money `json:"base_price,omitempty"`
+	AuctionEnd   *time.Time      `json:"auction_end,omitempty"`
+	ReservePrice *services.Money `json:"reserve_price,omitempty"`
+	BuyNowPrice  *services.Money `json:"buy_now_price,omitempty"`
+	CategoryID   *uuid.UUID      `json:"category_id,omitempty"`
+	Tags         []string        `json:"tags,omitempty"`
 }
 
-const minAuctionDuration = 2 * time.Hour
+// ListingCurrency is the currency the product is priced in.
+func (req CreateProductReq) ListingCurrency() string {
+	if req.Currency == "" {
+		return services.DefaultCurrency
+	}
+	return req.Currency
+}
+
+// Priced returns req with its amounts in minor units of the listing
+// currency. Bare amounts are decoded before the currency is known, so they
+// are rescaled here; Valid reports the ones that cannot be.
+func (req CreateProductReq) Priced() CreateProductReq {
+	currency := req.ListingCurrency()
+	for _, price := range req.prices() {
+		if converted, err := price.In(currency); err == nil {
+			*price = converted
+		}
+	}
+	return req
+}
+
+// prices lists the amounts held in the listing currency, which includes the
+// increment unless it is a percentage.
+func (req *CreateProductReq) prices() map[string]*services.Money {
+	prices := map[string]*services.Money{
+		"baseprice":         &req.Baseprice,
+		"reserve_price":     &req.ReservePrice,
+		"buy_now_price":     &req.BuyNowPrice,
+		"dutch_start_price": &req.DutchStartPrice,
+		"dutch_floor_price": &req.DutchFloorPrice,
+		"dutch_price_step":  &req.DutchPriceStep,
+	}
+	if req.IncrementType != services.IncrementPercentage {
+		prices["increment_value"] = &req.IncrementValue
+	}
+	return prices
+}
+
+func (req CreateProductReq) Classification() services.ProductClassification {
+	return services.ProductClassification{CategoryID: req.CategoryID, Tags: services.NormalizeTags(req.Tags)}
+}
+
+// Classification leaves the tags unchanged when the request has no tags
+// field; an empty list removes them.
+func (req UpdateProductReq) Classification() services.ProductClassification {
+	return services.ProductClassification{CategoryID: req.CategoryID, Tags: services.NormalizeTags(req.Tags)}
+}
+
+const (
+	maxSoftCloseMinutes = 60
+	minDutchInterval    = 10
+)
 
 func (req UpdateProductReq) Valid(ctx context.Context) validator.Evaluator {
 	var eval validator.Evaluator
@@ -36,11 +113,18 @@ func (req UpdateProductReq) Valid(ctx context.Context) validator.Evaluator {
 			validator.MaxChars(*req.Description, 3500), "description", "your description must have a minimum of 35 and a maximum of 3500 characters")
 	}
 	if req.Baseprice != nil {
-		eval.CheckField(*req.Baseprice > 0, "baseprice", "the product value must be at least greater than zero")
+		eval.CheckField(req.Baseprice.Amount > 0, "baseprice", "the product value must be at least greater than zero")
 	}
 	if req.AuctionEnd != nil {
-		eval.CheckField(req.AuctionEnd.Sub(time.Now()) >= minAuctionDuration, "auction_end", "the duration must be at least two hours")
+		eval.CheckField(req.AuctionEnd.Sub(time.Now()) >= services.MinAuctionDuration, "auction_end", "the duration must be at least two hours")
 	}
+	if req.ReservePrice != nil {
+		eval.CheckField(req.ReservePrice.Amount >= 0, "reserve_price", "the reserve price cannot be negative")
+	}
+	if req.BuyNowPrice != nil {
+		eval.CheckField(req.BuyNowPrice.Amount >= 0, "buy_now_price", "the buy now price cannot be negative")
+	}
+	checkTags(&eval, req.Tags)
 	return eval
 }
 
@@ -51,7 +135,69 @@ func (req CreateProductReq) Valid(ctx context.Context) validator.Evaluator {
 	eval.CheckField(validator.NotBlank(req.Description), "description", "this field cannot be blank")
 	eval.CheckField(validator.MinChars(req.Description, 35) &&
 		validator.MaxChars(req.Description, 3500), "description", "your description must have a minimum of 35 and a maximum of 3500 characters")
-	eval.CheckField(req.Baseprice > 0, "baseprice", "the product value must be at least greater than zero")
-	eval.CheckField(req.AuctionEnd.Sub(time.Now()) >= minAuctionDuration, "auction_end", "the duration must be at least two hours")
+	eval.CheckField(services.IsSupportedCurrency(req.ListingCurrency()), "currency",
+		"the currency must be one of "+strings.Join(services.SupportedCurrencies(), ", "))
+	for field, price := range req.prices() {
+		checkPrice(&eval, field, *price, req.ListingCurrency())
+	}
+	req = req.Priced()
+	eval.CheckField(req.Baseprice.Amount > 0, "baseprice", "the product value must be at least greater than zero")
+	start := time.Now()
+	if req.AuctionStart != nil {
+		eval.CheckField(req.AuctionStart.After(start), "auction_start", "the auction must start in the future")
+		start = *req.AuctionStart
+	}
+	eval.CheckField(req.AuctionEnd.Sub(start) >= services.MinAuctionDuration, "auction_end", "the duration must be at least two hours")
+	eval.CheckField(req.SoftCloseWindow >= 0 && req.SoftCloseWindow <= maxSoftCloseMinutes,
+		"soft_close_window_minutes", "the soft close window must be between 0 and 60 minutes")
+	eval.CheckField(req.SoftCloseExtension >= 0 && req.SoftCloseExtension <= maxSoftCloseMinutes,
+		"soft_close_extension_minutes", "the soft close extension must be between 0 and 60 minutes")
+	if req.SoftCloseWindow > 0 {
+		eval.CheckField(req.SoftCloseExtension > 0, "soft_close_extension_minutes", "an extension is required when a soft close window is set")
+	}
+	eval.CheckField(req.ReservePrice.Amount == 0 || req.ReservePrice.Amount >= req.Baseprice.Amount,
+		"reserve_price", "the reserve price cannot be lower than the base price")
+	eval.CheckField(req.BuyNowPrice.Amount == 0 || req.BuyNowPrice.Amount > max(req.Baseprice.Amount, req.ReservePrice.Amount),
+		"buy_now_price", "the buy now price must be greater than the base price and the reserve price")
+	eval.CheckField(validator.PermittedValue(req.IncrementType, "", "fixed", "percentage", "tiered"),
+		"increment_type", "the increment type must be fixed, percentage or tiered")
+	switch req.IncrementType {
+	case "fixed":
+		eval.CheckField(req.IncrementValue.Amount > 0, "increment_value", "the increment must be greater than zero")
+	case "percentage":
+		eval.CheckField(req.IncrementValue.Amount > 0 && req.IncrementValue.Amount <= 100_00, "increment_value", "the increment must be a percentage between 0 and 100")
+	}
+	eval.CheckField(validator.PermittedValue(req.AuctionType, "", "english", "dutch", "sealed", "vickrey"),
+		"auction_type", "the auction type must be english, dutch, sealed or vickrey")
+	if req.AuctionType == "dutch" {
+		eval.CheckField(req.DutchFloorPrice.Amount > 0, "dutch_floor_price", "the floor price must be greater than zero")
+		eval.CheckField(req.DutchStartPrice.Amount > req.DutchFloorPrice.Amount, "dutch_start_price", "the start price must be greater than the floor price")
+		eval.CheckField(req.DutchPriceStep.Amount > 0, "dutch_price_step", "the price step must be greater than zero")
+		eval.CheckField(req.ReservePrice.Amount <= req.DutchFloorPrice.Amount, "reserve_price", "the reserve price cannot be higher than the floor price")
+		eval.CheckField(req.DutchStepInterval >= minDutchInterval, "dutch_step_interval_seconds", "the price cannot drop more often than every 10 seconds")
+	}
+	if req.AuctionType != "" && req.AuctionType != "english" {
+		eval.CheckField(req.BuyNowPrice.Amount == 0, "buy_now_price", "only english auctions can have a buy now price")
+	}
+	checkTags(&eval, req.Tags)
 	return eval
 }
+
+func checkPrice(eval *validator.Evaluator, field string, price services.Money, currency string) {
+	_, err := price.In(currency)
+	if errors.Is(err, services.ErrCurrencyMismatch) {
+		eval.AddFieldError(field, "the amount must be in the currency of the product")
+	} else if err != nil {
+		eval.AddFieldError(field, "the amount has more decimal places than the currency of the product")
+	}
+}
+
+func checkTags(eval *validator.Evaluator, tags []string) {
+	tags = services.NormalizeTags(tags)
+	eval.CheckField(len(tags) <= services.MaxProductTags, "tags", "a product cannot have more than 10 tags")
+	eval.CheckField(validator.Unique(tags), "tags", "the tags must be unique")
+	for _, tag := range tags {
+		eval.CheckField(validator.MaxChars(tag, 50) && validator.Matches(tag, validator.SlugRX),
+			"tags", "tags can only have letters, numbers and hyphens, up to 50 characters")
+	}
+}
@@ -0,0 +1,150 @@
+package product
+
+import (
+	"context"
+	"fmt"
+	"github.com/FelipePn10/Gobid/internal/services"
+	"github.com/FelipePn10/Gobid/internal/validator"
+	"github.com/google/uuid"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// ListProductsReq is read from the query string of the catalogue endpoint.
+// Prices are decimals in Currency, which defaults to the listing default
+// when a price bound is given.
+type ListProductsReq struct {
+	Query        string
+	Status       string
+	Category     string
+	Tag          string
+	SellerID     *uuid.UUID
+	Currency     string
+	MinPrice     *services.Money
+	MaxPrice     *services.Money
+	EndingBefore *time.Time
+	Sort         string
+	Cursor       string
+	Limit        int32
+
+	problems validator.Evaluator
+}
+
+const maxSearchChars = 200
+
+// ParseListProductsReq reads the catalogue filters from the query string.
+// Values that cannot be parsed are reported by Valid.
+func ParseListProductsReq(query url.Values) ListProductsReq {
+	req := ListProductsReq{
+		Query:    query.Get("q"),
+		Status:   query.Get("status"),
+		Category: query.Get("category"),
+		Tag:      services.NormalizeTag(query.Get("tag")),
+		Currency: query.Get("currency"),
+		Sort:     query.Get("sort"),
+		Cursor:   query.Get("cursor"),
+		Limit:    services.DefaultProductPageSize,
+	}
+	if raw := query.Get("seller_id"); raw != "" {
+		sellerId, err := uuid.Parse(raw)
+		if err != nil {
+			req.problems.AddFieldError("seller_id", "must be a valid uuid")
+		} else {
+			req.SellerID = &sellerId
+		}
+	}
+	if (query.Has("min_price") || query.Has("max_price")) && req.Currency == "" {
+		req.Currency = services.DefaultCurrency
+	}
+	req.MinPrice = req.parsePrice(query, "min_price")
+	req.MaxPrice = req.parsePrice(query, "max_price")
+	if raw := query.Get("ending_before"); raw != "" {
+		endingBefore, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			req.problems.AddFieldError("ending_before", "must be an RFC 3339 timestamp")
+		} else {
+			req.EndingBefore = &endingBefore
+		}
+	}
+	if raw := query.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > services.MaxProductPageSize {
+			req.problems.AddFieldError("limit", "must be a number between 1 and 100")
+		} else {
+			req.Limit = int32(limit)
+		}
+	}
+	return req
+}
+
+func (req *ListProductsReq) parsePrice(query url.Values, key string) *services.Money {
+	raw := query.Get(key)
+	if raw == "" {
+		return nil
+	}
+	price, err := services.ParseMoney(raw, req.Currency)
+	if err != nil {
+		req.problems.AddFieldError(key, amountFormatMessage(req.Currency))
+		return nil
+	}
+	return &price
+}
+
+func amountFormatMessage(currency string) string {
+	decimals := services.CurrencyDecimals(currency)
+	if decimals == 0 {
+		return "must be a whole amount in " + currency
+	}
+	return fmt.Sprintf("must be a decimal amount with at most %d decimal places in %s", decimals, currency)
+}
+
+func (req ListProductsReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+	for key, message := range req.problems {
+		eval.AddFieldError(key, message)
+	}
+
+	eval.CheckField(validator.MaxChars(req.Query, maxSearchChars), "q", "the search cannot be longer than 200 characters")
+	if req.Status != "" {
+		eval.CheckField(validator.PermittedValue(req.Status,
+			services.ProductScheduled, services.ProductLive, services.ProductEnded, services.ProductSold,
+			services.ProductPaid, services.ProductCancelled,
+		), "status", "must be one of scheduled, live, ended, sold, paid or cancelled")
+	}
+	if req.Sort != "" {
+		eval.CheckField(validator.PermittedValue(req.Sort,
+			services.SortEndingSoon, services.SortMostBids, services.SortNewest,
+		), "sort", "must be one of ending_soon, most_bids or newest")
+	}
+	if req.Currency != "" {
+		eval.CheckField(services.IsSupportedCurrency(req.Currency), "currency", "unsupported currency")
+	}
+	if req.MinPrice != nil {
+		eval.CheckField(req.MinPrice.Amount >= 0, "min_price", "the minimum price cannot be negative")
+	}
+	if req.MinPrice != nil && req.MaxPrice != nil {
+		eval.CheckField(req.MaxPrice.Amount >= req.MinPrice.Amount, "max_price", "the maximum price cannot be lower than the minimum price")
+	}
+	return eval
+}
+
+// Filter is the catalogue filter described by the request.
+func (req ListProductsReq) Filter() services.ProductFilter {
+	filter := services.ProductFilter{
+		Search:       req.Query,
+		Status:       req.Status,
+		Category:     req.Category,
+		Tag:          req.Tag,
+		SellerID:     req.SellerID,
+		Currency:     req.Currency,
+		EndingBefore: req.EndingBefore,
+	}
+	if req.MinPrice != nil {
+		filter.MinPrice = &req.MinPrice.Amount
+	}
+	if req.MaxPrice != nil {
+		filter.MaxPrice = &req.MaxPrice.Amount
+	}
+	return filter
+}
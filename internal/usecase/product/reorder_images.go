@@ -0,0 +1,21 @@
+package product
+
+import (
+	"context"
+	"github.com/FelipePn10/Gobid/internal/services"
+	"github.com/FelipePn10/Gobid/internal/validator"
+	"github.com/google/uuid"
+)
+
+type ReorderImagesReq struct {
+	ImageIDs []uuid.UUID `json:"image_ids"`
+}
+
+func (req ReorderImagesReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	eval.CheckField(len(req.ImageIDs) > 0 && len(req.ImageIDs) <= services.MaxProductImages,
+		"image_ids", "list between 1 and 10 images")
+	eval.CheckField(validator.Unique(req.ImageIDs), "image_ids", "an image cannot be listed twice")
+	return eval
+}
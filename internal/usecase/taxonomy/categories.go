@@ -0,0 +1,76 @@
+package taxonomy
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/FelipePn10/Gobid/internal/validator"
+	"github.com/google/uuid"
+)
+
+type CreateCategoryReq struct {
+	ParentID *uuid.UUID `json:"parent_id,omitempty"`
+	Name     string     `json:"name"`
+	Slug     string     `json:"slug"`
+}
+
+// UpdateCategoryReq moves the category only when parent_id is present in the
+// body; "parent_id": null moves it to the top of the tree.
+type UpdateCategoryReq struct {
+	Name     *string         `json:"name,omitempty"`
+	Slug     *string         `json:"slug,omitempty"`
+	ParentID json.RawMessage `json:"parent_id,omitempty"`
+}
+
+type RenameTagReq struct {
+	Name string `json:"name"`
+}
+
+const maxCategoryChars = 80
+
+func (req CreateCategoryReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	eval.CheckField(validator.NotBlank(req.Name), "name", "this field cannot be blank")
+	eval.CheckField(validator.MaxChars(req.Name, maxCategoryChars), "name", "the name cannot be longer than 80 characters")
+	checkSlug(&eval, req.Slug)
+	return eval
+}
+
+func (req UpdateCategoryReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	if req.Name != nil {
+		eval.CheckField(validator.NotBlank(*req.Name), "name", "this field cannot be blank")
+		eval.CheckField(validator.MaxChars(*req.Name, maxCategoryChars), "name", "the name cannot be longer than 80 characters")
+	}
+	if req.Slug != nil {
+		checkSlug(&eval, *req.Slug)
+	}
+	if _, _, err := req.Parent(); err != nil {
+		eval.AddFieldError("parent_id", "must be a valid uuid or null")
+	}
+	return eval
+}
+
+// Parent reports whether the request moves the category and where to.
+func (req UpdateCategoryReq) Parent() (move bool, parentId *uuid.UUID, err error) {
+	if len(req.ParentID) == 0 {
+		return false, nil, nil
+	}
+	if err := json.Unmarshal(req.ParentID, &parentId); err != nil {
+		return false, nil, err
+	}
+	return true, parentId, nil
+}
+
+func (req RenameTagReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	eval.CheckField(validator.NotBlank(req.Name), "name", "this field cannot be blank")
+	return eval
+}
+
+func checkSlug(eval *validator.Evaluator, slug string) {
+	eval.CheckField(validator.MaxChars(slug, maxCategoryChars) && validator.Matches(slug, validator.SlugRX),
+		"slug", "the slug can only have lowercase letters, numbers and hyphens, up to 80 characters")
+}
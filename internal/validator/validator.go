@@ -13,6 +13,10 @@ type Validator interface {
 
 var EmailRX = regexp.MustCompile(`^[a-zA-Z]{3,4}(\d{6})((\D|\d){3})?$`)
 
+// SlugRX matches lowercase words joined by single hyphens, as used for
+// category slugs and tag names.
+var SlugRX = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
 type Evaluator map[string]string
 
 func (e *Evaluator) AddFieldError(key, message string) {
@@ -46,3 +50,23 @@ func MinChars(value string, n int) bool {
 func Matches(value string, rx *regexp.Regexp) bool {
 	return rx.MatchString(value)
 }
+
+func PermittedValue[T comparable](value T, permittedValues ...T) bool {
+	for _, permitted := range permittedValues {
+		if value == permitted {
+			return true
+		}
+	}
+	return false
+}
+
+func Unique[T comparable](values []T) bool {
+	seen := make(map[T]bool, len(values))
+	for _, value := range values {
+		if seen[value] {
+			return false
+		}
+		seen[value] = true
+	}
+	return true
+}